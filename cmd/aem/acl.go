@@ -0,0 +1,147 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wttech/aemc/pkg"
+	"github.com/wttech/aemc/pkg/auth"
+	"github.com/wttech/aemc/pkg/common/mapsx"
+)
+
+func (c *CLI) aclCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "acl",
+		Short: "Manage access control entries on repository paths",
+	}
+	cmd.AddCommand(c.aclReadCmd())
+	cmd.AddCommand(c.aclSaveCmd(auth.ACETypeAllow))
+	cmd.AddCommand(c.aclSaveCmd(auth.ACETypeDeny))
+	cmd.AddCommand(c.aclDeleteCmd())
+	return cmd
+}
+
+func (c *CLI) aclReadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "read",
+		Short:   "Read access control entries defined on path",
+		Aliases: []string{"get"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			acl := aclByFlags(cmd, *instance)
+			c.SetOutput("acl", acl)
+			c.Ok("ACL read")
+		},
+	}
+	aclDefineFlags(cmd)
+	return cmd
+}
+
+func (c *CLI) aclSaveCmd(aceType string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   aceType,
+		Short: "Save access control entry which " + aceType + "s privileges",
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			principal, _ := cmd.Flags().GetString("principal")
+			privileges, _ := cmd.Flags().GetStringSlice("privilege")
+			restrictions, _ := cmd.Flags().GetStringToString("restriction")
+			ace := auth.ACE{Principal: principal, Type: aceType, Privileges: privileges, Restrictions: restrictions}
+			if err := ace.Validate(); err != nil {
+				c.Error(err)
+				return
+			}
+			saved, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				acl := aclByFlags(cmd, instance)
+				changed, err := acl.SaveWithChanged(ace)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"acl":         acl,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("saved", saved)
+			if mapsx.SomeHas(saved, OutputChanged, true) {
+				c.Changed("ACE saved")
+			} else {
+				c.Ok("ACE already saved (up-to-date)")
+			}
+		},
+	}
+	aclDefineFlags(cmd)
+	aclDefinePrincipalFlags(cmd)
+	cmd.Flags().StringSlice("privilege", []string{}, "Privilege (e.g. 'jcr:read', repeatable)")
+	_ = cmd.MarkFlagRequired("privilege")
+	cmd.Flags().StringToString("restriction", map[string]string{}, "Restriction (e.g. 'rep:glob=/*/jcr:content*', repeatable)")
+	return cmd
+}
+
+func (c *CLI) aclDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete",
+		Short:   "Delete access control entries of principal defined on path",
+		Aliases: []string{"del", "remove"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			principal, _ := cmd.Flags().GetString("principal")
+			deleted, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				acl := aclByFlags(cmd, instance)
+				changed, err := acl.DeleteWithChanged(principal)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"acl":         acl,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("deleted", deleted)
+			if mapsx.SomeHas(deleted, OutputChanged, true) {
+				c.Changed("ACEs deleted")
+			} else {
+				c.Ok("ACEs already deleted (do not exist)")
+			}
+		},
+	}
+	aclDefineFlags(cmd)
+	aclDefinePrincipalFlags(cmd)
+	return cmd
+}
+
+func aclDefineFlags(cmd *cobra.Command) {
+	cmd.Flags().String("path", "", "Repository path")
+	_ = cmd.MarkFlagRequired("path")
+}
+
+func aclDefinePrincipalFlags(cmd *cobra.Command) {
+	cmd.Flags().String("principal", "", "Principal (user or group ID)")
+	_ = cmd.MarkFlagRequired("principal")
+}
+
+func aclByFlags(cmd *cobra.Command, instance pkg.Instance) *pkg.ACL {
+	path, _ := cmd.Flags().GetString("path")
+	acl := instance.Auth().ACL(path)
+	return &acl
+}
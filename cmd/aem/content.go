@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/wttech/aemc/pkg"
+	"github.com/wttech/aemc/pkg/common/mapsx"
+	"github.com/wttech/aemc/pkg/common/pathx"
+	"github.com/wttech/aemc/pkg/common/timex"
+)
+
+func (c *CLI) contentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "content",
+		Short:   "Manage JCR content",
+		Aliases: []string{"cnt"},
+	}
+	cmd.AddCommand(c.contentSyncCmd())
+	return cmd
+}
+
+func (c *CLI) contentSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "sync",
+		Short:   "Copy content from source instance to current ones",
+		Aliases: []string{"copy", "cp"},
+		Run: func(cmd *cobra.Command, args []string) {
+			sourceURL, _ := cmd.Flags().GetString("source-url")
+			source, err := c.aem.InstanceManager().NewByURL(sourceURL)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			targets, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			filters, err := c.pkgFiltersByFlags(cmd)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			file := fmt.Sprintf("%s/content/%s.zip", c.aem.BaseOpts().TmpDir, timex.FileTimestampForNow())
+			defer func() { _ = pathx.DeleteIfExists(file) }()
+			if err = source.PackageManager().Export(filters, file); err != nil {
+				c.Error(err)
+				return
+			}
+			synced, err := pkg.InstanceProcess(c.aem, targets, func(instance pkg.Instance) (map[string]any, error) {
+				if instance.HTTP().BaseURL() == source.HTTP().BaseURL() {
+					return map[string]any{
+						OutputChanged: false,
+						"instance":    instance,
+					}, nil
+				}
+				if err := instance.PackageManager().Import(file); err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: true,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			if err := c.aem.InstanceManager().AwaitStarted(InstancesChanged(synced)); err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("source", source)
+			c.SetOutput("synced", synced)
+			if mapsx.SomeHas(synced, OutputChanged, true) {
+				c.Changed("content synced")
+			} else {
+				c.Ok("content not synced (source is the only instance)")
+			}
+		},
+	}
+	cmd.Flags().String("source-url", "", "URL of instance to copy content from")
+	_ = cmd.MarkFlagRequired("source-url")
+	cmd.Flags().StringSlice("filter", []string{}, "Filter root path (repeatable, when omitted filters are read from input)")
+	return cmd
+}
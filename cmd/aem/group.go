@@ -0,0 +1,219 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wttech/aemc/pkg"
+	"github.com/wttech/aemc/pkg/common/mapsx"
+)
+
+func (c *CLI) groupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "group",
+		Aliases: []string{"grp"},
+		Short:   "Manage groups and their members",
+	}
+	cmd.AddCommand(c.groupReadCmd())
+	cmd.AddCommand(c.groupSaveCmd())
+	cmd.AddCommand(c.groupDeleteCmd())
+	cmd.AddCommand(c.groupAddMemberCmd())
+	cmd.AddCommand(c.groupRemoveMemberCmd())
+	return cmd
+}
+
+func (c *CLI) groupReadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "read",
+		Short:   "Read group details",
+		Aliases: []string{"get"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			group := groupByFlags(cmd, *instance)
+			c.SetOutput("group", group)
+			c.Ok("group read")
+		},
+	}
+	groupDefineFlags(cmd)
+	return cmd
+}
+
+func (c *CLI) groupSaveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "save",
+		Short:   "Create group if it does not exist",
+		Aliases: []string{"create"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			intermediatePath, _ := cmd.Flags().GetString("intermediate-path")
+			saved, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				group := groupByFlags(cmd, instance)
+				changed, err := group.CreateWithChanged(intermediatePath)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"group":       group,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("saved", saved)
+			if mapsx.SomeHas(saved, OutputChanged, true) {
+				c.Changed("group saved")
+			} else {
+				c.Ok("group already saved (up-to-date)")
+			}
+		},
+	}
+	groupDefineFlags(cmd)
+	cmd.Flags().String("intermediate-path", "", "Path relative to '/home/groups' under which group will be created")
+	return cmd
+}
+
+func (c *CLI) groupDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete",
+		Short:   "Delete group",
+		Aliases: []string{"del", "remove"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			deleted, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				group := groupByFlags(cmd, instance)
+				changed, err := group.DeleteWithChanged()
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"group":       group,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("deleted", deleted)
+			if mapsx.SomeHas(deleted, OutputChanged, true) {
+				c.Changed("group deleted")
+			} else {
+				c.Ok("group already deleted (does not exist)")
+			}
+		},
+	}
+	groupDefineFlags(cmd)
+	return cmd
+}
+
+func (c *CLI) groupAddMemberCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "add-member",
+		Short:   "Add users or groups to group",
+		Aliases: []string{"add"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			members, _ := cmd.Flags().GetStringSlice("member")
+			added, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				group := groupByFlags(cmd, instance)
+				changed, err := group.AddMembersWithChanged(members)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"group":       group,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("added", added)
+			if mapsx.SomeHas(added, OutputChanged, true) {
+				c.Changed("group members added")
+			} else {
+				c.Ok("group members already added (up-to-date)")
+			}
+		},
+	}
+	groupDefineFlags(cmd)
+	groupDefineMemberFlags(cmd)
+	return cmd
+}
+
+func (c *CLI) groupRemoveMemberCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove-member",
+		Short:   "Remove users or groups from group",
+		Aliases: []string{"rm"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			members, _ := cmd.Flags().GetStringSlice("member")
+			removed, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				group := groupByFlags(cmd, instance)
+				changed, err := group.RemoveMembersWithChanged(members)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"group":       group,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("removed", removed)
+			if mapsx.SomeHas(removed, OutputChanged, true) {
+				c.Changed("group members removed")
+			} else {
+				c.Ok("group members already removed (up-to-date)")
+			}
+		},
+	}
+	groupDefineFlags(cmd)
+	groupDefineMemberFlags(cmd)
+	return cmd
+}
+
+func groupDefineFlags(cmd *cobra.Command) {
+	cmd.Flags().String("id", "", "Group ID")
+	_ = cmd.MarkFlagRequired("id")
+}
+
+func groupDefineMemberFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("member", []string{}, "User or group ID (repeatable)")
+	_ = cmd.MarkFlagRequired("member")
+}
+
+func groupByFlags(cmd *cobra.Command, instance pkg.Instance) *pkg.Group {
+	id, _ := cmd.Flags().GetString("id")
+	group := instance.Auth().GroupManager().ByID(id)
+	return &group
+}
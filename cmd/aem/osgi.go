@@ -5,6 +5,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wttech/aemc/pkg"
 	"github.com/wttech/aemc/pkg/common/mapsx"
+	"github.com/wttech/aemc/pkg/maven"
+	"github.com/wttech/aemc/pkg/osgi"
 )
 
 func (c *CLI) osgiCmd() *cobra.Command {
@@ -33,6 +35,7 @@ func (c *CLI) osgiBundleCmd() *cobra.Command {
 	cmd.AddCommand(c.osgiBundleStartCmd())
 	cmd.AddCommand(c.osgiBundleStopCmd())
 	cmd.AddCommand(c.osgiBundleRestartCmd())
+	cmd.AddCommand(c.osgiBundleDiagnoseCmd())
 	return cmd
 }
 
@@ -41,12 +44,16 @@ func (c *CLI) osgiBundleInstall() *cobra.Command {
 		Use:   "install",
 		Short: "Install OSGi bundle(s)",
 		Run: func(cmd *cobra.Command, args []string) {
-			path, _ := cmd.Flags().GetString("file")
 			instances, err := c.aem.InstanceManager().Some()
 			if err != nil {
 				c.Error(err)
 				return
 			}
+			path, err := c.osgiBundlePathByFlags(cmd)
+			if err != nil {
+				c.Error(err)
+				return
+			}
 			installed, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
 				changed, err := instance.OSGI().BundleManager().InstallWithChanged(path)
 				if err != nil {
@@ -147,6 +154,33 @@ func (c *CLI) osgiBundleListCmd() *cobra.Command {
 	return cmd
 }
 
+func (c *CLI) osgiBundleDiagnoseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "diagnose",
+		Short:   "Explain why OSGi bundles are not active",
+		Aliases: []string{"diag"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			diagnosis, err := instance.OSGI().BundleManager().Diagnose()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("diagnosis", diagnosis)
+			if diagnosis.Empty() {
+				c.Ok("bundles diagnosed (no problems)")
+			} else {
+				c.Ok("bundles diagnosed (problems found)")
+			}
+		},
+	}
+	return cmd
+}
+
 func (c *CLI) osgiBundleReadCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "read",
@@ -304,7 +338,20 @@ func (c *CLI) osgiBundleRestartCmd() *cobra.Command {
 
 func osgiBundleDefineFileFlag(cmd *cobra.Command) {
 	cmd.Flags().String("file", "", "Local bundle JAR file")
-	_ = cmd.MarkFlagRequired("file")
+	cmd.Flags().String("artifact", "", "Maven coordinates of bundle JAR (groupId:artifactId[:extension[:classifier]]:version)")
+	cmd.MarkFlagsMutuallyExclusive("file", "artifact")
+}
+
+func (c *CLI) osgiBundlePathByFlags(cmd *cobra.Command) (string, error) {
+	artifact, _ := cmd.Flags().GetString("artifact")
+	if len(artifact) > 0 {
+		return c.aem.MavenOpts().Resolve(artifact, maven.ExtensionJar)
+	}
+	file, _ := cmd.Flags().GetString("file")
+	if len(file) > 0 {
+		return file, nil
+	}
+	return "", fmt.Errorf("flag 'file' or 'artifact' are required")
 }
 
 func osgiBundleDefineFlags(cmd *cobra.Command) {
@@ -527,6 +574,9 @@ func (c *CLI) osgiConfigCmd() *cobra.Command {
 	cmd.AddCommand(c.osgiConfigRead())
 	cmd.AddCommand(c.osgiConfigSave())
 	cmd.AddCommand(c.osgiConfigDelete())
+	cmd.AddCommand(c.osgiConfigApply())
+	cmd.AddCommand(c.osgiConfigExport())
+	cmd.AddCommand(c.osgiConfigFactoryCmd())
 	return cmd
 }
 
@@ -664,6 +714,267 @@ func (c *CLI) osgiConfigDelete() *cobra.Command {
 	return cmd
 }
 
+func (c *CLI) osgiConfigApply() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply OSGi configurations from directory",
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			dir, _ := cmd.Flags().GetString("dir")
+			prune, _ := cmd.Flags().GetBool("prune")
+			prunePrefixes, _ := cmd.Flags().GetStringSlice("prune-prefix")
+			if prune && len(prunePrefixes) == 0 {
+				c.Fail("flag 'prune-prefix' is required when pruning configs")
+				return
+			}
+			if !prune {
+				prunePrefixes = nil
+			}
+			applied, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				report, err := instance.OSGI().ConfigManager().ApplyDir(dir, prunePrefixes)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: report.Changed(),
+					"report":      report,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			if err := c.aem.InstanceManager().AwaitStarted(InstancesChanged(applied)); err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("applied", applied)
+			if mapsx.SomeHas(applied, OutputChanged, true) {
+				c.Changed("configs applied")
+			} else {
+				c.Ok("configs already applied (up-to-date)")
+			}
+		},
+	}
+	cmd.Flags().String("dir", "", "Directory with config files ('*.cfg.json', '*.config') or with run mode subdirs ('config', 'config.author', etc)")
+	_ = cmd.MarkFlagRequired("dir")
+	cmd.Flags().Bool("prune", false, "Delete configs not present in directory")
+	cmd.Flags().StringSlice("prune-prefix", []string{}, "PID prefixes of configs managed by directory (e.g. 'com.acme.')")
+	return cmd
+}
+
+func (c *CLI) osgiConfigExport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export OSGi configurations to directory",
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			pidPattern, _ := cmd.Flags().GetString("pid-pattern")
+			dir, _ := cmd.Flags().GetString("dir")
+			format, _ := cmd.Flags().GetString("format")
+			files, err := instance.OSGI().ConfigManager().Export(pidPattern, dir, format)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("instance", instance)
+			c.SetOutput("dir", dir)
+			c.SetOutput("files", files)
+			c.Changed("configs exported")
+		},
+	}
+	cmd.Flags().String("pid-pattern", "", "PID pattern (e.g. 'com.mycorp.*')")
+	_ = cmd.MarkFlagRequired("pid-pattern")
+	cmd.Flags().String("dir", "", "Directory to write config files to")
+	_ = cmd.MarkFlagRequired("dir")
+	cmd.Flags().String("format", osgi.ConfigExportFormatJSON, "Config file format")
+	return cmd
+}
+
+func (c *CLI) osgiConfigFactoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "factory",
+		Aliases: []string{"fct"},
+		Short:   "Manage OSGi factory configurations",
+	}
+	cmd.AddCommand(c.osgiConfigFactoryList())
+	cmd.AddCommand(c.osgiConfigFactoryRead())
+	cmd.AddCommand(c.osgiConfigFactorySave())
+	cmd.AddCommand(c.osgiConfigFactoryDelete())
+	return cmd
+}
+
+func (c *CLI) osgiConfigFactoryList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List OSGi factory configurations",
+		Aliases: []string{"ls"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			configs, err := osgiConfigFactoryFromFlag(cmd, *instance).List()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("configs", configs)
+			c.Ok("factory configs listed")
+		},
+	}
+	osgiConfigFactoryDefineFlags(cmd)
+	return cmd
+}
+
+func (c *CLI) osgiConfigFactoryRead() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "read",
+		Short:   "Read OSGi factory configuration values",
+		Aliases: []string{"get"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			name, _ := cmd.Flags().GetString("name")
+			config, err := osgiConfigFactoryFromFlag(cmd, *instance).ByName(name)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("config", config)
+			c.Ok("factory config read")
+		},
+	}
+	osgiConfigFactoryDefineFlags(cmd)
+	osgiConfigFactoryDefineNameFlag(cmd)
+	return cmd
+}
+
+func (c *CLI) osgiConfigFactorySave() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "save",
+		Short:   "Save OSGi factory configuration values",
+		Aliases: []string{"set"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			var props map[string]any
+			if err := c.ReadInput(&props); err != nil {
+				c.Fail(fmt.Sprintf("cannot save factory config as input props cannot be parsed: %s", err))
+				return
+			}
+			name, _ := cmd.Flags().GetString("name")
+			saved, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				factory := osgiConfigFactoryFromFlag(cmd, instance)
+				changed, err := factory.SaveWithChanged(name, props)
+				if err != nil {
+					return nil, err
+				}
+				config, err := factory.ByName(name)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"config":      config,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			if err := c.aem.InstanceManager().AwaitStarted(InstancesChanged(saved)); err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("saved", saved)
+			if mapsx.SomeHas(saved, OutputChanged, true) {
+				c.Changed("factory config saved")
+			} else {
+				c.Ok("factory config already saved (up-to-date)")
+			}
+		},
+	}
+	osgiConfigFactoryDefineFlags(cmd)
+	osgiConfigFactoryDefineNameFlag(cmd)
+	return cmd
+}
+
+func (c *CLI) osgiConfigFactoryDelete() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete",
+		Short:   "Delete OSGi factory configuration",
+		Aliases: []string{"del", "remove", "unset"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			name, _ := cmd.Flags().GetString("name")
+			deleted, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				changed, err := osgiConfigFactoryFromFlag(cmd, instance).DeleteWithChanged(name)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			if err := c.aem.InstanceManager().AwaitStarted(InstancesChanged(deleted)); err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("deleted", deleted)
+			if mapsx.SomeHas(deleted, OutputChanged, true) {
+				c.Changed("factory config deleted")
+			} else {
+				c.Ok("factory config already deleted (does not exist)")
+			}
+		},
+	}
+	osgiConfigFactoryDefineFlags(cmd)
+	osgiConfigFactoryDefineNameFlag(cmd)
+	return cmd
+}
+
+func osgiConfigFactoryDefineFlags(cmd *cobra.Command) {
+	cmd.Flags().String("factory-pid", "", "Factory PID")
+	_ = cmd.MarkFlagRequired("factory-pid")
+}
+
+func osgiConfigFactoryDefineNameFlag(cmd *cobra.Command) {
+	cmd.Flags().String("name", "", "Config name (part of PID after '~') or generated UUID")
+	_ = cmd.MarkFlagRequired("name")
+}
+
+func osgiConfigFactoryFromFlag(cmd *cobra.Command, i pkg.Instance) pkg.OSGiConfigFactory {
+	factoryPID, _ := cmd.Flags().GetString("factory-pid")
+	return i.OSGI().ConfigManager().Factory(factoryPID)
+}
+
 func osgiConfigDefineFlags(cmd *cobra.Command) {
 	cmd.Flags().String("pid", "", "PID")
 	_ = cmd.MarkFlagRequired("pid")
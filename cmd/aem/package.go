@@ -2,11 +2,15 @@ package main
 
 import (
 	"fmt"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/wttech/aemc/pkg"
 	"github.com/wttech/aemc/pkg/common/httpx"
 	"github.com/wttech/aemc/pkg/common/mapsx"
 	"github.com/wttech/aemc/pkg/common/pathx"
+	"github.com/wttech/aemc/pkg/common/timex"
+	"github.com/wttech/aemc/pkg/maven"
+	pkgdef "github.com/wttech/aemc/pkg/pkg"
 	"strings"
 )
 
@@ -17,6 +21,7 @@ func (c *CLI) pkgCmd() *cobra.Command {
 		Aliases: []string{"pkg"},
 	}
 	cmd.AddCommand(c.pkgListCmd())
+	cmd.AddCommand(c.pkgCreateCmd())
 	cmd.AddCommand(c.pkgUploadCmd())
 	cmd.AddCommand(c.pkgInstallCmd())
 	cmd.AddCommand(c.pkgDeployCmd())
@@ -24,7 +29,12 @@ func (c *CLI) pkgCmd() *cobra.Command {
 	cmd.AddCommand(c.pkgDeleteCmd())
 	cmd.AddCommand(c.pkgPurgeCmd())
 	cmd.AddCommand(c.pkgBuildCmd())
+	cmd.AddCommand(c.pkgDownloadCmd())
+	cmd.AddCommand(c.pkgInspectCmd())
+	cmd.AddCommand(c.pkgAssembleCmd())
 	cmd.AddCommand(c.pkgFindCmd())
+	cmd.AddCommand(c.pkgRollbackCmd())
+	cmd.AddCommand(c.pkgHistoryCmd())
 	return cmd
 }
 
@@ -54,7 +64,7 @@ func (c *CLI) pkgFindCmd() *cobra.Command {
 }
 
 func (c *CLI) pkgListCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "list",
 		Short:   "List packages",
 		Aliases: []string{"ls"},
@@ -64,7 +74,12 @@ func (c *CLI) pkgListCmd() *cobra.Command {
 				c.Error(err)
 				return
 			}
-			pkgs, err := instance.PackageManager().List()
+			filter, err := pkgListFilterByFlags(cmd)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			pkgs, err := instance.PackageManager().ListBy(*filter)
 			if err != nil {
 				c.Error(err)
 				return
@@ -74,6 +89,105 @@ func (c *CLI) pkgListCmd() *cobra.Command {
 			c.Ok("packages listed")
 		},
 	}
+	cmd.Flags().String("group", "", "Group")
+	cmd.Flags().String("name", "", "Name (glob pattern)")
+	cmd.Flags().Bool("installed", false, "Only installed")
+	cmd.Flags().Bool("built", false, "Only built")
+	cmd.Flags().String("modified-after", "", "Only modified after date (e.g. '2023-01-31' or '2023-01-31 12:00:00')")
+	cmd.Flags().String("sort", "", fmt.Sprintf("Sort by (%s)", strings.Join(pkgdef.ListSorts(), "|")))
+	cmd.Flags().Int("limit", 0, "Limit number of packages")
+	return cmd
+}
+
+func pkgListFilterByFlags(cmd *cobra.Command) (*pkgdef.ListFilter, error) {
+	result := &pkgdef.ListFilter{}
+	result.Group, _ = cmd.Flags().GetString("group")
+	result.Name, _ = cmd.Flags().GetString("name")
+	result.Installed, _ = cmd.Flags().GetBool("installed")
+	result.Built, _ = cmd.Flags().GetBool("built")
+	result.Sort, _ = cmd.Flags().GetString("sort")
+	result.Limit, _ = cmd.Flags().GetInt("limit")
+	modifiedAfter, _ := cmd.Flags().GetString("modified-after")
+	if modifiedAfter != "" {
+		value, err := timex.Parse(modifiedAfter)
+		if err != nil {
+			return nil, err
+		}
+		result.ModifiedAfter = value
+	}
+	return result, nil
+}
+
+func (c *CLI) pkgCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Create package",
+		Aliases: []string{"make"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			pid, _ := cmd.Flags().GetString("pid")
+			filters, err := c.pkgFiltersByFlags(cmd)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			build, _ := cmd.Flags().GetBool("build")
+			created, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				changed, err := instance.PackageManager().CreateWithChanged(pid, filters)
+				if err != nil {
+					return nil, err
+				}
+				p, err := instance.PackageManager().ByPID(pid)
+				if err != nil {
+					return nil, err
+				}
+				if build && changed {
+					if err := p.Build(); err != nil {
+						return nil, err
+					}
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"package":     p,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("created", created)
+			if mapsx.SomeHas(created, OutputChanged, true) {
+				c.Changed("package created")
+			} else {
+				c.Ok("package already created (up-to-date)")
+			}
+		},
+	}
+	cmd.Flags().String("pid", "", "ID (group:name:version)")
+	_ = cmd.MarkFlagRequired("pid")
+	cmd.Flags().StringSlice("filter", []string{}, "Filter root path (repeatable, when omitted filters are read from input)")
+	cmd.Flags().Bool("build", false, "Build package after creating or updating it")
+	return cmd
+}
+
+func (c *CLI) pkgFiltersByFlags(cmd *cobra.Command) ([]pkgdef.Filter, error) {
+	roots, _ := cmd.Flags().GetStringSlice("filter")
+	if len(roots) > 0 {
+		return pkgdef.NewFilters(roots), nil
+	}
+	var definition pkgdef.FilterDefinition
+	if err := c.ReadInput(&definition); err != nil {
+		return nil, fmt.Errorf("cannot read package filters from input: %w", err)
+	}
+	if len(definition.Filters) == 0 {
+		return nil, fmt.Errorf("package filters are required; specify flag 'filter' or provide them as input")
+	}
+	return definition.Filters, nil
 }
 
 func (c *CLI) pkgUploadCmd() *cobra.Command {
@@ -136,20 +250,42 @@ func (c *CLI) pkgInstallCmd() *cobra.Command {
 				c.Error(err)
 				return
 			}
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
 			installed, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				pkgApplyInstallFlags(cmd, instance.PackageManager())
 				p, err := pkgByFlags(cmd, instance)
 				if err != nil {
 					return nil, err
 				}
+				if dryRun {
+					installLog, err := p.DryRun()
+					if err != nil {
+						return nil, err
+					}
+					return map[string]any{
+						OutputChanged: false,
+						"package":     p,
+						"log":         installLog,
+						"instance":    instance,
+					}, nil
+				}
 				changed, err := p.InstallWithChanged()
 				if err != nil {
 					return nil, err
 				}
-				return map[string]any{
+				result := map[string]any{
 					OutputChanged: changed,
 					"package":     p,
 					"instance":    instance,
-				}, nil
+				}
+				if changed {
+					installLog, err := p.InstallLog()
+					if err != nil {
+						return nil, err
+					}
+					result["log"] = installLog
+				}
+				return result, nil
 			})
 			if err != nil {
 				c.Error(err)
@@ -160,7 +296,9 @@ func (c *CLI) pkgInstallCmd() *cobra.Command {
 				return
 			}
 			c.SetOutput("installed", installed)
-			if mapsx.SomeHas(installed, OutputChanged, true) {
+			if dryRun {
+				c.Ok("package install dry-run completed")
+			} else if mapsx.SomeHas(installed, OutputChanged, true) {
 				c.Changed("package installed")
 			} else {
 				c.Ok("package already installed (up-to-date)")
@@ -168,6 +306,7 @@ func (c *CLI) pkgInstallCmd() *cobra.Command {
 		},
 	}
 	pkgDefineFlags(cmd)
+	pkgDefineInstallFlags(cmd)
 	return cmd
 }
 
@@ -181,32 +320,23 @@ func (c *CLI) pkgDeployCmd() *cobra.Command {
 				c.Error(err)
 				return
 			}
-			path, err := c.pkgPathByFlags(cmd)
+			paths, err := c.pkgPathsByFlags(cmd)
 			if err != nil {
 				c.Error(err)
 				return
 			}
+			many := pkgFileFlagMany(cmd)
 			force, _ := cmd.Flags().GetBool("force")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
 			deployed, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
-				changed := false
-				if force {
-					err = instance.PackageManager().Deploy(path)
-					changed = true
-				} else {
-					changed, err = instance.PackageManager().DeployWithChanged(path)
+				pkgApplyInstallFlags(cmd, instance.PackageManager())
+				if dryRun {
+					return pkgDeployDryRun(instance, paths)
 				}
-				if err != nil {
-					return nil, err
-				}
-				p, err := instance.PackageManager().ByFile(path)
-				if err != nil {
-					return nil, err
+				if !many {
+					return pkgDeployOne(instance, paths[0], force)
 				}
-				return map[string]any{
-					OutputChanged: changed,
-					"package":     p,
-					"instance":    instance,
-				}, nil
+				return pkgDeployAll(instance, paths, force)
 			})
 			if err != nil {
 				c.Error(err)
@@ -217,6 +347,14 @@ func (c *CLI) pkgDeployCmd() *cobra.Command {
 				return
 			}
 			c.SetOutput("deployed", deployed)
+			if dryRun {
+				if lo.SomeBy(deployed, func(o map[string]any) bool { return len(o["uploaded"].([]string)) > 0 }) {
+					c.Ok("package deploy dry-run completed (packages not yet uploaded were uploaded temporarily then deleted)")
+				} else {
+					c.Ok("package deploy dry-run completed (already uploaded packages checked)")
+				}
+				return
+			}
 			if mapsx.SomeHas(deployed, OutputChanged, true) {
 				c.Changed("package deployed")
 			} else {
@@ -225,10 +363,195 @@ func (c *CLI) pkgDeployCmd() *cobra.Command {
 		},
 	}
 	pkgDefineFileAndUrlFlags(cmd)
+	cmd.Flags().Lookup("file").Usage = "Local ZIP path, glob pattern or directory (dependencies are deployed first)"
 	cmd.Flags().BoolP("force", "f", false, "Deploy even when already deployed")
+	pkgDefineInstallFlags(cmd)
 	return cmd
 }
 
+func pkgDeployDryRun(instance pkg.Instance, paths []string) (map[string]any, error) {
+	ordered, err := instance.PackageManager().ResolveDeployOrder(paths)
+	if err != nil {
+		return nil, err
+	}
+	logs := map[string]*pkgdef.InstallLog{}
+	var uploaded []string
+	for _, path := range ordered {
+		installLog, pathUploaded, err := instance.PackageManager().DeployDryRun(path)
+		if err != nil {
+			return nil, err
+		}
+		logs[path] = installLog
+		if pathUploaded {
+			uploaded = append(uploaded, path)
+		}
+	}
+	return map[string]any{
+		OutputChanged: false,
+		"logs":        logs,
+		"uploaded":    uploaded,
+		"instance":    instance,
+	}, nil
+}
+
+func pkgDefineInstallFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("recursive", true, "Install sub-packages too")
+	cmd.Flags().String("ac-handling", "", fmt.Sprintf("Access control handling (%s)", strings.Join(pkgdef.ACHandlings(), "|")))
+	cmd.Flags().Int("autosave", 0, "Number of nodes after which changes are saved")
+	cmd.Flags().String("dependency-handling", "", fmt.Sprintf("Dependency handling (%s)", strings.Join(pkgdef.DependencyHandlings(), "|")))
+	cmd.Flags().Bool("dry-run", false, "Only report what would be changed (package not yet uploaded is uploaded temporarily then deleted)")
+}
+
+// pkgApplyInstallFlags overrides install options from configuration but only by flags set explicitly
+func pkgApplyInstallFlags(cmd *cobra.Command, pm *pkg.PackageManager) {
+	if cmd.Flags().Changed("recursive") {
+		pm.InstallRecursive, _ = cmd.Flags().GetBool("recursive")
+	}
+	if cmd.Flags().Changed("ac-handling") {
+		pm.InstallACHandling, _ = cmd.Flags().GetString("ac-handling")
+	}
+	if cmd.Flags().Changed("autosave") {
+		pm.InstallAutosave, _ = cmd.Flags().GetInt("autosave")
+	}
+	if cmd.Flags().Changed("dependency-handling") {
+		pm.InstallDependencyHandling, _ = cmd.Flags().GetString("dependency-handling")
+	}
+}
+
+func pkgDeployOne(instance pkg.Instance, path string, force bool) (map[string]any, error) {
+	var err error
+	changed := false
+	if force {
+		err = instance.PackageManager().Deploy(path)
+		changed = true
+	} else {
+		changed, err = instance.PackageManager().DeployWithChanged(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p, err := instance.PackageManager().ByFile(path)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{
+		OutputChanged: changed,
+		"package":     p,
+		"instance":    instance,
+	}
+	if changed {
+		installLog, err := instance.PackageManager().InstallLog(path)
+		if err != nil {
+			return nil, err
+		}
+		result["log"] = installLog
+	}
+	return result, nil
+}
+
+func pkgDeployAll(instance pkg.Instance, paths []string, force bool) (map[string]any, error) {
+	var err error
+	var deployedPaths []string
+	if force {
+		deployedPaths, err = instance.PackageManager().DeployAll(paths)
+	} else {
+		deployedPaths, err = instance.PackageManager().DeployAllWithChanged(paths)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var packages []*pkg.Package
+	logs := map[string]*pkgdef.InstallLog{}
+	for _, path := range deployedPaths {
+		p, err := instance.PackageManager().ByFile(path)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, p)
+		installLog, err := instance.PackageManager().InstallLog(path)
+		if err != nil {
+			return nil, err
+		}
+		logs[p.PID.String()] = installLog
+	}
+	return map[string]any{
+		OutputChanged: len(deployedPaths) > 0,
+		"packages":    packages,
+		"logs":        logs,
+		"instance":    instance,
+	}, nil
+}
+
+func (c *CLI) pkgRollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Rollback package to previously installed version",
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			pid, _ := cmd.Flags().GetString("pid")
+			rolledBack, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				current, previous, err := instance.PackageManager().Rollback(pid)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: true,
+					"from":        current,
+					"to":          previous,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			if err := c.aem.InstanceManager().AwaitStarted(InstancesChanged(rolledBack)); err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("rolledBack", rolledBack)
+			c.Changed("package rolled back")
+		},
+	}
+	cmd.Flags().String("pid", "", "ID (group:name)")
+	_ = cmd.MarkFlagRequired("pid")
+	return cmd
+}
+
+func (c *CLI) pkgHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "Show history of package deployments",
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			histories, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				history, err := instance.PackageManager().History()
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					"history":  history,
+					"instance": instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("histories", histories)
+			c.Ok("package history read")
+		},
+	}
+}
+
 func (c *CLI) pkgUninstallCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "uninstall",
@@ -329,6 +652,11 @@ func (c *CLI) pkgPurgeCmd() *cobra.Command {
 				c.Error(err)
 				return
 			}
+			if pkgPurgePolicyDefined(cmd) {
+				c.pkgPurgeByPolicy(cmd, instances)
+				return
+			}
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
 			purged, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
 				p, err := pkgByFlags(cmd, instance)
 				if err != nil {
@@ -338,6 +666,14 @@ func (c *CLI) pkgPurgeCmd() *cobra.Command {
 				if err != nil {
 					return nil, err
 				}
+				if dryRun {
+					return map[string]any{
+						OutputChanged: false,
+						"purgeable":   state.Exists,
+						"package":     p,
+						"instance":    instance,
+					}, nil
+				}
 				changed := false
 				if state.Exists {
 					if state.Data.Installed() {
@@ -370,7 +706,9 @@ func (c *CLI) pkgPurgeCmd() *cobra.Command {
 				return
 			}
 			c.SetOutput("purged", purged)
-			if mapsx.SomeHas(purged, OutputChanged, true) {
+			if dryRun {
+				c.Ok("package purge dry-run completed")
+			} else if mapsx.SomeHas(purged, OutputChanged, true) {
 				c.Changed("package purged")
 			} else {
 				c.Ok("package already purged")
@@ -378,9 +716,58 @@ func (c *CLI) pkgPurgeCmd() *cobra.Command {
 		},
 	}
 	pkgDefineFlags(cmd)
+	cmd.Flags().Int("keep-versions", 0, "Purge by policy: number of most recent versions of each package to keep")
+	cmd.Flags().String("older-than", "", "Purge by policy: only packages not used for a duration (e.g. '30d' or '12h')")
+	cmd.Flags().String("group", "", "Purge by policy: only packages in group")
+	cmd.Flags().Bool("snapshots-only", false, "Purge by policy: only snapshot packages")
+	cmd.Flags().Bool("dry-run", false, "Only list packages to be purged")
 	return cmd
 }
 
+func pkgPurgePolicyDefined(cmd *cobra.Command) bool {
+	return lo.SomeBy([]string{"keep-versions", "older-than", "group", "snapshots-only"}, func(name string) bool { return cmd.Flags().Changed(name) })
+}
+
+func (c *CLI) pkgPurgeByPolicy(cmd *cobra.Command, instances []pkg.Instance) {
+	policy := pkgdef.PurgePolicy{}
+	policy.KeepVersions, _ = cmd.Flags().GetInt("keep-versions")
+	policy.Group, _ = cmd.Flags().GetString("group")
+	policy.SnapshotsOnly, _ = cmd.Flags().GetBool("snapshots-only")
+	olderThan, _ := cmd.Flags().GetString("older-than")
+	if olderThan != "" {
+		duration, err := timex.ParseDuration(olderThan)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		policy.OlderThan = duration
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	purged, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+		result, err := instance.PackageManager().PurgeBy(policy, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			OutputChanged: !dryRun && len(result.Packages) > 0,
+			"result":      result,
+			"instance":    instance,
+		}, nil
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.SetOutput("purged", purged)
+	if dryRun {
+		c.Ok("packages to be purged listed")
+	} else if mapsx.SomeHas(purged, OutputChanged, true) {
+		c.Changed("packages purged")
+	} else {
+		c.Ok("no packages to purge")
+	}
+}
+
 func (c *CLI) pkgBuildCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "build",
@@ -414,6 +801,125 @@ func (c *CLI) pkgBuildCmd() *cobra.Command {
 	return cmd
 }
 
+func (c *CLI) pkgDownloadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "download",
+		Short:   "Download package",
+		Aliases: []string{"dwn"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			file, _ := cmd.Flags().GetString("file")
+			p, err := pkgByRemoteFlags(cmd, *instance)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			if err = p.Download(file); err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("package", p)
+			c.SetOutput("instance", instance)
+			c.SetOutput("file", file)
+			c.Changed("package downloaded")
+		},
+	}
+	cmd.Flags().String("pid", "", "ID (group:name:version)")
+	cmd.Flags().String("path", "", "Remote path on AEM repository")
+	cmd.MarkFlagsMutuallyExclusive("pid", "path")
+	cmd.Flags().String("file", "", "Local ZIP path to save package to")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func pkgByRemoteFlags(cmd *cobra.Command, instance pkg.Instance) (*pkg.Package, error) {
+	pid, _ := cmd.Flags().GetString("pid")
+	if len(pid) > 0 {
+		return instance.PackageManager().ByPID(pid)
+	}
+	path, _ := cmd.Flags().GetString("path")
+	if len(path) > 0 {
+		return instance.PackageManager().ByPath(path)
+	}
+	return nil, fmt.Errorf("flag 'pid' or 'path' are required")
+}
+
+func (c *CLI) pkgAssembleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "assemble",
+		Short:   "Assemble package file from local content (offline)",
+		Aliases: []string{"compose"},
+		Run: func(cmd *cobra.Command, args []string) {
+			pid, _ := cmd.Flags().GetString("pid")
+			pidConfig, err := pkgdef.ParsePID(pid)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			sourceDir, _ := cmd.Flags().GetString("source-dir")
+			file, _ := cmd.Flags().GetString("file")
+			roots, _ := cmd.Flags().GetStringSlice("filter")
+			description, _ := cmd.Flags().GetString("description")
+			assembly := pkgdef.Assembly{
+				SourceDir:   sourceDir,
+				PID:         *pidConfig,
+				Filters:     pkgdef.NewFilters(roots),
+				Description: description,
+			}
+			if err := assembly.Assemble(file); err != nil {
+				c.Error(err)
+				return
+			}
+			inspection, err := pkgdef.Inspect(file)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("inspection", inspection)
+			c.Changed("package assembled")
+		},
+	}
+	cmd.Flags().String("source-dir", "", "Local dir with content ('jcr_root' or its parent)")
+	_ = cmd.MarkFlagRequired("source-dir")
+	cmd.Flags().String("pid", "", "ID (group:name:version)")
+	_ = cmd.MarkFlagRequired("pid")
+	cmd.Flags().String("file", "", "Local ZIP path to be created")
+	_ = cmd.MarkFlagRequired("file")
+	cmd.Flags().StringSlice("filter", []string{}, "Filter root path (repeatable, when omitted filters are taken from 'META-INF/vault/filter.xml' or derived from content dirs)")
+	cmd.Flags().String("description", "", "Description")
+	return cmd
+}
+
+func (c *CLI) pkgInspectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "inspect",
+		Short:   "Inspect package file (offline)",
+		Aliases: []string{"describe"},
+		Run: func(cmd *cobra.Command, args []string) {
+			file, _ := cmd.Flags().GetString("file")
+			fileGlobbed, err := pathx.GlobSome(file)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			inspection, err := pkgdef.Inspect(fileGlobbed)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("inspection", inspection)
+			c.Ok("package inspected")
+		},
+	}
+	cmd.Flags().String("file", "", "Local ZIP path")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
 func pkgDefineFlags(cmd *cobra.Command) {
 	cmd.Flags().String("pid", "", "ID (group:name:version)'")
 	cmd.Flags().String("file", "", "Local path on file system")
@@ -447,10 +953,15 @@ func pkgByFlags(cmd *cobra.Command, instance pkg.Instance) (*pkg.Package, error)
 func pkgDefineFileAndUrlFlags(cmd *cobra.Command) {
 	cmd.Flags().String("file", "", "Local ZIP path")
 	cmd.Flags().String("url", "", "URL to ZIP")
-	cmd.MarkFlagsMutuallyExclusive("file", "url")
+	cmd.Flags().String("artifact", "", "Maven coordinates of ZIP (groupId:artifactId[:extension[:classifier]]:version)")
+	cmd.MarkFlagsMutuallyExclusive("file", "url", "artifact")
 }
 
 func (c *CLI) pkgPathByFlags(cmd *cobra.Command) (string, error) {
+	artifact, _ := cmd.Flags().GetString("artifact")
+	if len(artifact) > 0 {
+		return c.aem.MavenOpts().Resolve(artifact, maven.ExtensionZip)
+	}
 	url, _ := cmd.Flags().GetString("url")
 	if len(url) > 0 {
 		fileName := httpx.FileNameFromURL(url)
@@ -472,5 +983,37 @@ func (c *CLI) pkgPathByFlags(cmd *cobra.Command) (string, error) {
 		}
 		return fileGlobbed, nil
 	}
-	return "", fmt.Errorf("flag 'file' or 'url' are required")
+	return "", fmt.Errorf("flag 'file', 'url' or 'artifact' are required")
+}
+
+// pkgFileFlagMany checks if flag 'file' points to a directory or is a glob pattern so that it may match many files
+func pkgFileFlagMany(cmd *cobra.Command) bool {
+	file, _ := cmd.Flags().GetString("file")
+	return len(file) > 0 && (pathx.IsDir(file) || strings.ContainsAny(file, "*?[{"))
+}
+
+// pkgPathsByFlags is like pkgPathByFlags but allows flag 'file' to point to a directory or to match many files
+func (c *CLI) pkgPathsByFlags(cmd *cobra.Command) ([]string, error) {
+	file, _ := cmd.Flags().GetString("file")
+	if !pkgFileFlagMany(cmd) {
+		path, err := c.pkgPathByFlags(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+	var paths []string
+	var err error
+	if pathx.IsDir(file) {
+		paths, err = pathx.GlobDir(file, "*.zip")
+	} else {
+		paths, err = pathx.GlobAll(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("cannot find any package file matching '%s'", file)
+	}
+	return paths, nil
 }
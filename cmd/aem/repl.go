@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/wttech/aemc/pkg"
 )
@@ -13,10 +14,122 @@ func (c *CLI) replCmd() *cobra.Command {
 		Aliases: []string{"repl"},
 	}
 	cmd.AddCommand(c.replAgentCmd())
+	cmd.AddCommand(c.replActivateCmd())
+	cmd.AddCommand(c.replDeactivateCmd())
 
 	return cmd
 }
 
+func (c *CLI) replActivateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "activate",
+		Short:   "Activate content paths",
+		Aliases: []string{"publish"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.replAuthors()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			paths, _ := cmd.Flags().GetStringSlice("path")
+			tree, _ := cmd.Flags().GetBool("tree")
+			onlyModified, _ := cmd.Flags().GetBool("only-modified")
+			activated, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				replication := replicationByFlags(cmd, instance)
+				if tree {
+					for _, path := range paths {
+						if err := replication.TreeActivate(path, onlyModified); err != nil {
+							return nil, err
+						}
+					}
+				} else if err := replication.Activate(paths); err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: true,
+					"paths":       paths,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("activated", activated)
+			c.Changed("paths activated")
+		},
+	}
+	replicationDefineFlags(cmd)
+	cmd.Flags().Bool("tree", false, "Activate paths with all descendants")
+	cmd.Flags().Bool("only-modified", false, "Skip descendants not modified since last activation (applicable to tree only)")
+	return cmd
+}
+
+func (c *CLI) replDeactivateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "deactivate",
+		Short:   "Deactivate content paths",
+		Aliases: []string{"unpublish"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.replAuthors()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			paths, _ := cmd.Flags().GetStringSlice("path")
+			deactivated, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				if err := replicationByFlags(cmd, instance).Deactivate(paths); err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: true,
+					"paths":       paths,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("deactivated", deactivated)
+			c.Changed("paths deactivated")
+		},
+	}
+	replicationDefineFlags(cmd)
+	return cmd
+}
+
+// replAuthors selects instances on which content could be replicated (publish instances are skipped)
+func (c *CLI) replAuthors() ([]pkg.Instance, error) {
+	instances, err := c.aem.InstanceManager().Some()
+	if err != nil {
+		return nil, err
+	}
+	authors := lo.Filter(instances, func(i pkg.Instance, _ int) bool { return i.IsAuthor() })
+	if len(authors) == 0 {
+		return nil, fmt.Errorf("no author instances selected to replicate content from")
+	}
+	return authors, nil
+}
+
+func replicationDefineFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("path", []string{}, "Content path (repeatable)")
+	_ = cmd.MarkFlagRequired("path")
+	cmd.Flags().String("agent", "", "Replication agent name (defaults to configured one)")
+	cmd.Flags().Duration("queue-timeout", 0, "Time to wait for replication queue to be empty (defaults to configured one)")
+}
+
+func replicationByFlags(cmd *cobra.Command, instance pkg.Instance) *pkg.Replication {
+	replication := *instance.Replication()
+	if agent, _ := cmd.Flags().GetString("agent"); agent != "" {
+		replication.AgentName = agent
+	}
+	if queueTimeout, _ := cmd.Flags().GetDuration("queue-timeout"); queueTimeout > 0 {
+		replication.QueueTimeout = queueTimeout
+	}
+	return &replication
+}
+
 func (c *CLI) replAgentCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "agent",
@@ -26,6 +139,8 @@ func (c *CLI) replAgentCmd() *cobra.Command {
 	cmd.AddCommand(c.replAgentReadCmd())
 	cmd.AddCommand(c.replAgentSetupCmd())
 	cmd.AddCommand(c.replAgentDeleteCmd())
+	cmd.AddCommand(c.replAgentQueueCmd())
+	cmd.AddCommand(c.replAgentTestConnectionCmd())
 
 	return cmd
 }
@@ -120,6 +235,121 @@ func (c *CLI) replAgentSetupCmd() *cobra.Command {
 	return cmd
 }
 
+func (c *CLI) replAgentQueueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Read replication agent queue",
+		Run: func(cmd *cobra.Command, args []string) {
+			i, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			queue, err := replAgentByFlags(cmd, i).Queue()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("queue", queue)
+			c.Ok("replication agent queue read")
+		},
+	}
+	replAgentDefineFlags(cmd)
+	cmd.AddCommand(c.replAgentQueueClearCmd())
+	cmd.AddCommand(c.replAgentQueueRetryCmd())
+	return cmd
+}
+
+func (c *CLI) replAgentQueueClearCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Clear replication agent queue",
+		Run: func(cmd *cobra.Command, args []string) {
+			i, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			replAgent := replAgentByFlags(cmd, i)
+			queue, err := replAgent.Queue()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			if queue.Empty() {
+				c.SetOutput("queue", queue)
+				c.Ok("replication agent queue already cleared (empty)")
+				return
+			}
+			if err := replAgent.QueueClear(); err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("queue", queue)
+			c.Changed("replication agent queue cleared")
+		},
+	}
+	replAgentDefineFlags(cmd)
+	return cmd
+}
+
+func (c *CLI) replAgentQueueRetryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retry",
+		Short: "Retry replicating blocked items of replication agent queue",
+		Run: func(cmd *cobra.Command, args []string) {
+			i, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			replAgent := replAgentByFlags(cmd, i)
+			queue, err := replAgent.Queue()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			if len(queue.BlockedItems()) == 0 {
+				c.SetOutput("queue", queue)
+				c.Ok("replication agent queue has no blocked items")
+				return
+			}
+			if err := replAgent.QueueRetry(); err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("queue", queue)
+			c.Changed("replication agent queue retried")
+		},
+	}
+	replAgentDefineFlags(cmd)
+	return cmd
+}
+
+func (c *CLI) replAgentTestConnectionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "test-connection",
+		Short:   "Test connection of replication agent",
+		Aliases: []string{"test"},
+		Run: func(cmd *cobra.Command, args []string) {
+			i, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			replAgent := replAgentByFlags(cmd, i)
+			if err := replAgent.TestConnection(); err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("replAgent", replAgent)
+			c.Ok("replication agent connection tested")
+		},
+	}
+	replAgentDefineFlags(cmd)
+	return cmd
+}
+
 func replAgentDefineFlags(cmd *cobra.Command) {
 	cmd.Flags().String("location", "", "Location")
 	_ = cmd.MarkFlagRequired("location")
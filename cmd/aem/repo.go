@@ -2,9 +2,13 @@ package main
 
 import (
 	"fmt"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/wttech/aemc/pkg"
 	"github.com/wttech/aemc/pkg/common/mapsx"
+	"github.com/wttech/aemc/pkg/common/pathx"
+	"github.com/wttech/aemc/pkg/repo"
+	"strconv"
 )
 
 func (c *CLI) repoCmd() *cobra.Command {
@@ -14,6 +18,9 @@ func (c *CLI) repoCmd() *cobra.Command {
 		Aliases: []string{"repo"},
 	}
 	cmd.AddCommand(c.repoNodeCmd())
+	cmd.AddCommand(c.repoQueryCmd())
+	cmd.AddCommand(c.repoFileCmd())
+	cmd.AddCommand(c.repoDiffCmd())
 
 	return cmd
 }
@@ -29,6 +36,10 @@ func (c *CLI) repoNodeCmd() *cobra.Command {
 	cmd.AddCommand(c.repoNodeCopyCmd())
 	cmd.AddCommand(c.repoNodeMoveCmd())
 	cmd.AddCommand(c.repoNodeChildrenCmd())
+	cmd.AddCommand(c.repoNodeExportCmd())
+	cmd.AddCommand(c.repoNodeUpdateAllCmd())
+	cmd.AddCommand(c.repoNodeDeleteAllCmd())
+	cmd.AddCommand(c.repoNodeImportCmd())
 
 	return cmd
 }
@@ -65,7 +76,13 @@ func (c *CLI) repoNodeChildrenCmd() *cobra.Command {
 				return
 			}
 			node := repoNodeByFlags(cmd, *instance)
-			children, err := node.Children()
+			var children []pkg.RepoNode
+			if recursive, _ := cmd.Flags().GetBool("recursive"); recursive {
+				children, err = repoTraversalByFlags(cmd, *node).Nodes()
+				children = lo.Filter(children, func(child pkg.RepoNode, _ int) bool { return child.Path() != node.Path() })
+			} else {
+				children, err = node.Children()
+			}
 			if err != nil {
 				c.Error(err)
 				return
@@ -75,9 +92,27 @@ func (c *CLI) repoNodeChildrenCmd() *cobra.Command {
 		},
 	}
 	repoNodeDefineFlags(cmd)
+	cmd.Flags().BoolP("recursive", "r", false, "Read all descendants instead of direct children only")
+	cmd.Flags().Int("max-depth", -1, "Maximum depth of descendants relative to node (negative means unlimited)")
+	cmd.Flags().StringSlice("include", []string{}, "Path pattern of descendants to be read (repeatable)")
+	cmd.Flags().StringSlice("exclude", []string{}, "Path pattern of descendants to be skipped with their subtrees (repeatable)")
+	cmd.Flags().StringSlice("node-type", []string{}, "Primary type of descendants to be read (e.g. 'cq:Page', repeatable)")
+	cmd.Flags().Int("concurrency", 0, "Number of requests sent at once (defaults to configured value)")
 	return cmd
 }
 
+func repoTraversalByFlags(cmd *cobra.Command, node pkg.RepoNode) pkg.RepoTraversal {
+	traversal := node.Traversal()
+	traversal.MaxDepth, _ = cmd.Flags().GetInt("max-depth")
+	traversal.Includes, _ = cmd.Flags().GetStringSlice("include")
+	traversal.Excludes, _ = cmd.Flags().GetStringSlice("exclude")
+	traversal.NodeTypes, _ = cmd.Flags().GetStringSlice("node-type")
+	if concurrency, _ := cmd.Flags().GetInt("concurrency"); concurrency > 0 {
+		traversal.Concurrency = concurrency
+	}
+	return traversal
+}
+
 func (c *CLI) repoNodeSaveCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "save",
@@ -256,6 +291,367 @@ func (c *CLI) repoNodeMoveCmd() *cobra.Command {
 	return cmd
 }
 
+func (c *CLI) repoNodeExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export node with descendants to local dir",
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			dir, _ := cmd.Flags().GetString("dir")
+			node := repoNodeByFlags(cmd, *instance)
+			paths, err := node.Export(dir)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("instance", instance)
+			c.SetOutput("dir", dir)
+			c.SetOutput("paths", paths)
+			c.Changed("node exported")
+		},
+	}
+	repoNodeDefineFlags(cmd)
+	cmd.Flags().String("dir", "", "Local dir (structured like 'jcr_root')")
+	_ = cmd.MarkFlagRequired("dir")
+	return cmd
+}
+
+func (c *CLI) repoNodeImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import node with descendants from local dir",
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			dir, _ := cmd.Flags().GetString("dir")
+			if !pathx.IsDir(dir) {
+				c.Error(fmt.Errorf("cannot import nodes as dir '%s' does not exist", dir))
+				return
+			}
+			imported, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				node := repoNodeByFlags(cmd, instance)
+				paths, err := node.ImportWithChanged(dir)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: len(paths) > 0,
+					"paths":       paths,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("imported", imported)
+			if mapsx.SomeHas(imported, OutputChanged, true) {
+				c.Changed("node imported")
+			} else {
+				c.Ok("node already imported (up-to-date)")
+			}
+		},
+	}
+	repoNodeDefineFlags(cmd)
+	cmd.Flags().String("dir", "", "Local dir (structured like 'jcr_root')")
+	_ = cmd.MarkFlagRequired("dir")
+	return cmd
+}
+
+func (c *CLI) repoDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare node with descendants between two instances",
+		Run: func(cmd *cobra.Command, args []string) {
+			sourceID, _ := cmd.Flags().GetString("source-id")
+			targetID, _ := cmd.Flags().GetString("target-id")
+			source, err := c.aem.InstanceManager().ByID(sourceID)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			target, err := c.aem.InstanceManager().ByID(targetID)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			diff, err := repoNodeByFlags(cmd, *source).Diff(*repoNodeByFlags(cmd, *target))
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("diff", diff)
+			if diff.Empty() {
+				c.Ok("nodes compared (no differences)")
+			} else {
+				c.Ok("nodes compared (differences found)")
+			}
+		},
+	}
+	repoNodeDefineFlags(cmd)
+	cmd.Flags().String("source-id", "", "Source instance ID")
+	_ = cmd.MarkFlagRequired("source-id")
+	cmd.Flags().String("target-id", "", "Target instance ID")
+	_ = cmd.MarkFlagRequired("target-id")
+	return cmd
+}
+
+func (c *CLI) repoFileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "file",
+		Short: "Transfer files to and from JCR repository",
+	}
+	cmd.AddCommand(c.repoFilePutCmd())
+	cmd.AddCommand(c.repoFileGetCmd())
+	return cmd
+}
+
+func (c *CLI) repoFilePutCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "put",
+		Short:   "Upload local file as node of type 'nt:file'",
+		Aliases: []string{"upload"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			file, _ := cmd.Flags().GetString("file")
+			if !pathx.Exists(file) || pathx.IsDir(file) {
+				c.Error(fmt.Errorf("cannot upload file as it does not exist '%s'", file))
+				return
+			}
+			mimeType, _ := cmd.Flags().GetString("mime-type")
+			uploaded, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				node := repoNodeByFlags(cmd, instance)
+				changed, err := node.UploadFileWithChanged(file, mimeType)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"node":        node,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("uploaded", uploaded)
+			if mapsx.SomeHas(uploaded, OutputChanged, true) {
+				c.Changed("file uploaded")
+			} else {
+				c.Ok("file already uploaded (up-to-date)")
+			}
+		},
+	}
+	repoNodeDefineFlags(cmd)
+	cmd.Flags().StringP("file", "f", "", "Local file path")
+	_ = cmd.MarkFlagRequired("file")
+	cmd.Flags().String("mime-type", "", "MIME type (detected by file extension if not specified)")
+	return cmd
+}
+
+func (c *CLI) repoFileGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "get",
+		Short:   "Download node of type 'nt:file' to local file",
+		Aliases: []string{"download"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			file, _ := cmd.Flags().GetString("file")
+			node := repoNodeByFlags(cmd, *instance)
+			if err = node.DownloadFile(file); err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("instance", instance)
+			c.SetOutput("node", node)
+			c.SetOutput("file", file)
+			c.Changed("file downloaded")
+		},
+	}
+	repoNodeDefineFlags(cmd)
+	cmd.Flags().StringP("file", "f", "", "Local file path")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func (c *CLI) repoNodeUpdateAllCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-all",
+		Short: "Update all nodes matching properties",
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			set, _ := cmd.Flags().GetStringToString("set")
+			props := lo.MapValues(set, func(v string, _ string) any { return v })
+			updated, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				report, err := repoBulkByFlags(cmd, instance).UpdateAllWithChanged(props)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: len(report.Changed) > 0,
+					"report":      report,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("updated", updated)
+			if mapsx.SomeHas(updated, OutputChanged, true) {
+				c.Changed("nodes updated")
+			} else {
+				c.Ok("nodes already updated (up-to-date)")
+			}
+		},
+	}
+	repoBulkDefineFlags(cmd)
+	cmd.Flags().StringToString("set", map[string]string{}, "Property to be set (e.g. 'sling:resourceType=new/comp', repeatable)")
+	_ = cmd.MarkFlagRequired("set")
+	return cmd
+}
+
+func (c *CLI) repoNodeDeleteAllCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-all",
+		Short: "Delete all nodes matching properties",
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			deleted, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				report, err := repoBulkByFlags(cmd, instance).DeleteAllWithChanged()
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: len(report.Changed) > 0,
+					"report":      report,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("deleted", deleted)
+			if mapsx.SomeHas(deleted, OutputChanged, true) {
+				c.Changed("nodes deleted")
+			} else {
+				c.Ok("nodes already deleted")
+			}
+		},
+	}
+	repoBulkDefineFlags(cmd)
+	return cmd
+}
+
+func repoBulkDefineFlags(cmd *cobra.Command) {
+	repoNodeDefineFlags(cmd)
+	cmd.Flags().StringToString("match", map[string]string{}, "Property value to be matched (e.g. 'sling:resourceType=old/comp', repeatable)")
+	_ = cmd.MarkFlagRequired("match")
+	cmd.Flags().Int("concurrency", 0, "Number of nodes processed at once (defaults to configured value)")
+	cmd.Flags().Bool("dry-run", false, "Only list nodes to be affected")
+}
+
+func repoBulkByFlags(cmd *cobra.Command, instance pkg.Instance) pkg.RepoBulk {
+	match, _ := cmd.Flags().GetStringToString("match")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	return pkg.RepoBulk{
+		Root:        *repoNodeByFlags(cmd, instance),
+		Match:       match,
+		Concurrency: concurrency,
+		DryRun:      dryRun,
+	}
+}
+
+func (c *CLI) repoQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "query",
+		Short:   "Find nodes using JCR-SQL2 or QueryBuilder",
+		Aliases: []string{"find", "search"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			nodes, err := repoQueryByFlags(cmd, *instance)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			propNames, _ := cmd.Flags().GetStringSlice("property")
+			hits := repo.QueryHits{Count: len(nodes), Hits: []repo.QueryHit{}}
+			for _, node := range nodes {
+				hit := repo.QueryHit{Path: node.Path()}
+				if len(propNames) > 0 {
+					props, err := node.ReadProps()
+					if err != nil {
+						c.Error(err)
+						return
+					}
+					hit.Properties = lo.PickByKeys(props, propNames)
+				}
+				hits.Hits = append(hits.Hits, hit)
+			}
+			c.SetOutput("instance", instance)
+			c.SetOutput("hits", hits)
+			c.Ok("nodes queried")
+		},
+	}
+	cmd.Flags().String("sql2", "", "JCR-SQL2 statement")
+	cmd.Flags().StringToString("param", map[string]string{}, "QueryBuilder predicate (e.g. 'type=cq:Page', repeatable)")
+	cmd.MarkFlagsMutuallyExclusive("sql2", "param")
+	cmd.Flags().Int("limit", 0, "Limit number of nodes")
+	cmd.Flags().Int("offset", 0, "Number of nodes to skip")
+	cmd.Flags().StringSlice("property", []string{}, "Property to be read from found nodes (repeatable)")
+	return cmd
+}
+
+func repoQueryByFlags(cmd *cobra.Command, instance pkg.Instance) ([]pkg.RepoNode, error) {
+	limit, _ := cmd.Flags().GetInt("limit")
+	offset, _ := cmd.Flags().GetInt("offset")
+	sql2, _ := cmd.Flags().GetString("sql2")
+	if sql2 != "" {
+		return instance.Repo().QueryRange(sql2, offset, limit)
+	}
+	params, _ := cmd.Flags().GetStringToString("param")
+	if len(params) == 0 {
+		return nil, fmt.Errorf("flag 'sql2' or 'param' is required")
+	}
+	if limit > 0 {
+		params["p.limit"] = strconv.Itoa(limit)
+	}
+	if offset > 0 {
+		params["p.offset"] = strconv.Itoa(offset)
+	}
+	return instance.Repo().QueryBuilder(params)
+}
+
 func repoNodeDefineFlags(cmd *cobra.Command) {
 	cmd.Flags().String("path", "", "Path")
 	_ = cmd.MarkFlagRequired("path")
@@ -25,8 +25,12 @@ func (c *CLI) rootCmd() *cobra.Command {
 	cmd.AddCommand(c.osgiCmd())
 	cmd.AddCommand(c.pkgCmd())
 	cmd.AddCommand(c.repoCmd())
+	cmd.AddCommand(c.contentCmd())
 	cmd.AddCommand(c.replCmd())
 	cmd.AddCommand(c.cryptoCmd())
+	cmd.AddCommand(c.userCmd())
+	cmd.AddCommand(c.groupCmd())
+	cmd.AddCommand(c.aclCmd())
 	cmd.AddCommand(c.fileCmd())
 	c.rootFlags(cmd)
 	return cmd
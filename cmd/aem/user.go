@@ -0,0 +1,187 @@
+package main
+
+import (
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/wttech/aemc/pkg"
+	"github.com/wttech/aemc/pkg/common/mapsx"
+)
+
+func (c *CLI) userCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "user",
+		Aliases: []string{"usr"},
+		Short:   "Manage users",
+	}
+	cmd.AddCommand(c.userReadCmd())
+	cmd.AddCommand(c.userSaveCmd())
+	cmd.AddCommand(c.userPasswordCmd())
+	cmd.AddCommand(c.userDeleteCmd())
+	return cmd
+}
+
+func (c *CLI) userReadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "read",
+		Short:   "Read user details",
+		Aliases: []string{"get"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instance, err := c.aem.InstanceManager().One()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			user := userByFlags(cmd, *instance)
+			c.SetOutput("user", user)
+			c.Ok("user read")
+		},
+	}
+	userDefineFlags(cmd)
+	return cmd
+}
+
+func (c *CLI) userSaveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "save",
+		Short:   "Create user if it does not exist and update its profile",
+		Aliases: []string{"create"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			password, _ := cmd.Flags().GetString("password")
+			system, _ := cmd.Flags().GetBool("system")
+			intermediatePath, _ := cmd.Flags().GetString("intermediate-path")
+			profile, _ := cmd.Flags().GetStringToString("profile")
+			saved, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				user := userByFlags(cmd, instance)
+				changed, err := user.CreateWithChanged(password, system, intermediatePath)
+				if err != nil {
+					return nil, err
+				}
+				if len(profile) > 0 {
+					profileChanged, err := user.SaveProfileWithChanged(lo.MapValues(profile, func(v string, _ string) any { return v }))
+					if err != nil {
+						return nil, err
+					}
+					changed = changed || profileChanged
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"user":        user,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("saved", saved)
+			if mapsx.SomeHas(saved, OutputChanged, true) {
+				c.Changed("user saved")
+			} else {
+				c.Ok("user already saved (up-to-date)")
+			}
+		},
+	}
+	userDefineFlags(cmd)
+	cmd.Flags().String("password", "", "Password (required for regular users)")
+	cmd.Flags().Bool("system", false, "Create system user (without password)")
+	cmd.Flags().String("intermediate-path", "", "Path relative to '/home/users' under which user will be created")
+	cmd.Flags().StringToString("profile", map[string]string{}, "Profile property to be set (e.g. 'email=john@example.com', repeatable)")
+	return cmd
+}
+
+func (c *CLI) userPasswordCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "password",
+		Short:   "Change user password",
+		Aliases: []string{"passwd"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			password, _ := cmd.Flags().GetString("password")
+			changed, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				user := userByFlags(cmd, instance)
+				changed, err := user.ChangePasswordWithChanged(password)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"user":        user,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("changed", changed)
+			if mapsx.SomeHas(changed, OutputChanged, true) {
+				c.Changed("user password changed")
+			} else {
+				c.Ok("user password already changed (up-to-date)")
+			}
+		},
+	}
+	userDefineFlags(cmd)
+	cmd.Flags().String("password", "", "New password")
+	_ = cmd.MarkFlagRequired("password")
+	return cmd
+}
+
+func (c *CLI) userDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete",
+		Short:   "Delete user",
+		Aliases: []string{"del", "remove"},
+		Run: func(cmd *cobra.Command, args []string) {
+			instances, err := c.aem.InstanceManager().Some()
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			deleted, err := pkg.InstanceProcess(c.aem, instances, func(instance pkg.Instance) (map[string]any, error) {
+				user := userByFlags(cmd, instance)
+				changed, err := user.DeleteWithChanged()
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					OutputChanged: changed,
+					"user":        user,
+					"instance":    instance,
+				}, nil
+			})
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.SetOutput("deleted", deleted)
+			if mapsx.SomeHas(deleted, OutputChanged, true) {
+				c.Changed("user deleted")
+			} else {
+				c.Ok("user already deleted (does not exist)")
+			}
+		},
+	}
+	userDefineFlags(cmd)
+	return cmd
+}
+
+func userDefineFlags(cmd *cobra.Command) {
+	cmd.Flags().String("id", "", "User ID")
+	_ = cmd.MarkFlagRequired("id")
+}
+
+func userByFlags(cmd *cobra.Command, instance pkg.Instance) *pkg.User {
+	id, _ := cmd.Flags().GetString("id")
+	user := instance.Auth().UserManager().ByID(id)
+	return &user
+}
@@ -0,0 +1,115 @@
+package pkg
+
+import (
+	"fmt"
+	"github.com/wttech/aemc/pkg/auth"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"github.com/wttech/aemc/pkg/common/stringsx"
+	"github.com/wttech/aemc/pkg/repo"
+	"net/http"
+)
+
+// Auth Facade for managing users, groups and their permissions.
+type Auth struct {
+	instance *Instance
+
+	userManager  *UserManager
+	groupManager *GroupManager
+}
+
+func NewAuth(instance *Instance) *Auth {
+	return &Auth{
+		instance: instance,
+
+		userManager:  &UserManager{instance: instance},
+		groupManager: &GroupManager{instance: instance},
+	}
+}
+
+func (a *Auth) UserManager() *UserManager {
+	return a.userManager
+}
+
+func (a *Auth) GroupManager() *GroupManager {
+	return a.groupManager
+}
+
+// ACL Creates a new access control list object for repository path.
+func (a *Auth) ACL(path string) ACL {
+	return ACL{instance: a.instance, path: path}
+}
+
+// findAuthorizable looks up user or group by ID as their nodes are stored under paths not derivable from ID
+func findAuthorizable(instance *Instance, id string) (*auth.Authorizable, error) {
+	response, err := instance.http.Request().
+		SetQueryParams(map[string]string{
+			"path":           AuthorizablesRoot,
+			"type":           "rep:Authorizable",
+			"property":       "rep:authorizableId",
+			"property.value": id,
+			"p.hits":         "selective",
+			"p.properties":   "jcr:path jcr:primaryType",
+			"p.limit":        "1",
+		}).
+		Get(QueryBuilderPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot find authorizable '%s': %w", instance.ID(), id, err)
+	} else if response.IsError() {
+		return nil, fmt.Errorf("%s > cannot find authorizable '%s': %s", instance.ID(), id, response.Status())
+	}
+	var result repo.QueryBuilderResult
+	if err = fmtx.UnmarshalJSON(response.RawBody(), &result); err != nil {
+		return nil, fmt.Errorf("%s > cannot parse authorizable '%s' search response: %w", instance.ID(), id, err)
+	}
+	if len(result.Hits) == 0 {
+		return nil, nil
+	}
+	hit := result.Hits[0]
+	return &auth.Authorizable{
+		ID:   id,
+		Path: fmt.Sprintf("%v", hit["jcr:path"]),
+		Type: fmt.Sprintf("%v", hit["jcr:primaryType"]),
+	}, nil
+}
+
+// postAuthorizable sends command to Granite authorizable servlet (e.g. 'deleteAuthorizable', 'addMembers')
+func postAuthorizable(instance *Instance, path string, action string, params map[string]string) error {
+	response, err := instance.http.Request().SetFormData(params).Post(path)
+	if err != nil {
+		return fmt.Errorf("%s > cannot %s: %w", instance.ID(), action, err)
+	} else if response.IsError() {
+		return fmt.Errorf("%s > cannot %s: %s", instance.ID(), action, response.Status())
+	}
+	return nil
+}
+
+// authorizableMembers reads IDs of declared members or groups using Sling user manager (e.g. 'declaredMembers', 'declaredMemberOf')
+func authorizableMembers(instance *Instance, path string, prop string) ([]string, error) {
+	response, err := instance.http.Request().Get(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot read authorizable '%s': %w", instance.ID(), path, err)
+	} else if response.StatusCode() == http.StatusNotFound {
+		return []string{}, nil
+	} else if response.IsError() {
+		return nil, fmt.Errorf("%s > cannot read authorizable '%s': %s", instance.ID(), path, response.Status())
+	}
+	var props map[string]any
+	if err = fmtx.UnmarshalJSON(response.RawBody(), &props); err != nil {
+		return nil, fmt.Errorf("%s > cannot parse authorizable '%s': %w", instance.ID(), path, err)
+	}
+	var result []string
+	if values, ok := props[prop].([]any); ok {
+		for _, value := range values {
+			result = append(result, stringsx.AfterLast(fmt.Sprintf("%v", value), "/"))
+		}
+	}
+	return result, nil
+}
+
+const (
+	AuthorizablesRoot       = "/home"
+	AuthorizablesPostPath   = "/libs/granite/security/post/authorizables"
+	AuthorizableCurrentPath = "/libs/granite/security/currentuser.json"
+	UserManagerUserPath     = "/system/userManager/user"
+	UserManagerGroupPath    = "/system/userManager/group"
+)
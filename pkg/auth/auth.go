@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	TypeUser       = "rep:User"
+	TypeSystemUser = "rep:SystemUser"
+	TypeGroup      = "rep:Group"
+)
+
+// Authorizable is a user or group found in repository
+type Authorizable struct {
+	ID   string `json:"id" yaml:"id"`
+	Path string `json:"path" yaml:"path"`
+	Type string `json:"type" yaml:"type"`
+}
+
+func (a Authorizable) IsGroup() bool {
+	return a.Type == TypeGroup
+}
+
+func (a Authorizable) IsSystemUser() bool {
+	return a.Type == TypeSystemUser
+}
+
+const (
+	ACETypeAllow = "allow"
+	ACETypeDeny  = "deny"
+
+	aceNodeTypeAllow = "rep:GrantACE"
+	aceNodeTypeDeny  = "rep:DenyACE"
+)
+
+func ACETypes() []string {
+	return []string{ACETypeAllow, ACETypeDeny}
+}
+
+// ACE is an access control entry defined on repository path for principal (user or group)
+type ACE struct {
+	Principal    string            `json:"principal" yaml:"principal"`
+	Type         string            `json:"type" yaml:"type"`
+	Privileges   []string          `json:"privileges" yaml:"privileges"`
+	Restrictions map[string]string `json:"restrictions,omitempty" yaml:"restrictions,omitempty"`
+}
+
+func (e ACE) Validate() error {
+	if e.Principal == "" {
+		return fmt.Errorf("ACE principal is not specified")
+	}
+	if !lo.Contains(ACETypes(), e.Type) {
+		return fmt.Errorf("ACE type '%s' is not supported; use one of: %s", e.Type, strings.Join(ACETypes(), ", "))
+	}
+	if len(e.Privileges) == 0 {
+		return fmt.Errorf("ACE privileges are not specified")
+	}
+	return nil
+}
+
+// Equals compares entries ignoring order of privileges
+func (e ACE) Equals(other ACE) bool {
+	return e.Principal == other.Principal && e.Type == other.Type &&
+		reflect.DeepEqual(sortedStrings(e.Privileges), sortedStrings(other.Privileges)) &&
+		reflect.DeepEqual(lo.Assign(e.Restrictions), lo.Assign(other.Restrictions))
+}
+
+func (e ACE) String() string {
+	return fmt.Sprintf("%s %s %s", e.Type, e.Principal, strings.Join(e.Privileges, ","))
+}
+
+func sortedStrings(values []string) []string {
+	result := append([]string{}, values...)
+	sort.Strings(result)
+	return result
+}
+
+// ACL is a list of access control entries defined on repository path
+type ACL struct {
+	Path    string `json:"path" yaml:"path"`
+	Entries []ACE  `json:"entries" yaml:"entries"`
+}
+
+// ParseACL reads entries from JSON representation of node 'rep:policy' (e.g. '/content/rep:policy.2.json')
+func ParseACL(path string, policy map[string]any) ACL {
+	result := ACL{Path: path, Entries: []ACE{}}
+	names := lo.Keys(policy)
+	sort.Strings(names)
+	for _, name := range names {
+		node, ok := policy[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		var aceType string
+		switch node["jcr:primaryType"] {
+		case aceNodeTypeAllow:
+			aceType = ACETypeAllow
+		case aceNodeTypeDeny:
+			aceType = ACETypeDeny
+		default:
+			continue
+		}
+		ace := ACE{
+			Principal:    fmt.Sprintf("%v", node["rep:principalName"]),
+			Type:         aceType,
+			Privileges:   anyStrings(node["rep:privileges"]),
+			Restrictions: map[string]string{},
+		}
+		if restrictions, ok := node["rep:restrictions"].(map[string]any); ok {
+			for restrictionName, value := range restrictions {
+				if restrictionName == "jcr:primaryType" {
+					continue
+				}
+				ace.Restrictions[restrictionName] = strings.Join(anyStrings(value), ",")
+			}
+		}
+		result.Entries = append(result.Entries, ace)
+	}
+	return result
+}
+
+func anyStrings(value any) []string {
+	if values, ok := value.([]any); ok {
+		return lo.Map(values, func(v any, _ int) string { return fmt.Sprintf("%v", v) })
+	}
+	if value == nil {
+		return []string{}
+	}
+	return []string{fmt.Sprintf("%v", value)}
+}
+
+// ByPrincipal returns entries defined for principal
+func (l ACL) ByPrincipal(principal string) []ACE {
+	return lo.Filter(l.Entries, func(e ACE, _ int) bool { return e.Principal == principal })
+}
+
+// Has checks if exactly the same entry is defined
+func (l ACL) Has(ace ACE) bool {
+	return lo.ContainsBy(l.Entries, func(e ACE) bool { return e.Equals(ace) })
+}
+
+func (l ACL) MarshalText() string {
+	bs := bytes.NewBufferString("")
+	bs.WriteString(fmt.Sprintf("ACL '%s'\n", l.Path))
+	bs.WriteString(fmtx.TblRows("entries", false, []string{"principal", "type", "privileges", "restrictions"}, lo.Map(l.Entries, func(e ACE, _ int) map[string]any {
+		restrictions := lo.MapToSlice(e.Restrictions, func(k string, v string) string { return k + "=" + v })
+		sort.Strings(restrictions)
+		return map[string]any{
+			"principal":    e.Principal,
+			"type":         e.Type,
+			"privileges":   strings.Join(e.Privileges, ", "),
+			"restrictions": strings.Join(restrictions, ", "),
+		}
+	})))
+	return bs.String()
+}
@@ -0,0 +1,50 @@
+package auth_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/auth"
+	"testing"
+)
+
+func TestParseACL(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	acl := auth.ParseACL("/content/my-site", map[string]any{
+		"jcr:primaryType": "rep:ACL",
+		"allow": map[string]any{
+			"jcr:primaryType":   "rep:GrantACE",
+			"rep:principalName": "content-authors",
+			"rep:privileges":    []any{"jcr:read", "rep:write"},
+			"rep:restrictions": map[string]any{
+				"jcr:primaryType": "rep:Restrictions",
+				"rep:glob":        "/*/jcr:content*",
+			},
+		},
+		"deny0": map[string]any{
+			"jcr:primaryType":   "rep:DenyACE",
+			"rep:principalName": "everyone",
+			"rep:privileges":    []any{"jcr:all"},
+		},
+	})
+
+	a.Len(acl.Entries, 2)
+	a.True(acl.Has(auth.ACE{
+		Principal:    "content-authors",
+		Type:         auth.ACETypeAllow,
+		Privileges:   []string{"rep:write", "jcr:read"},
+		Restrictions: map[string]string{"rep:glob": "/*/jcr:content*"},
+	}))
+	a.False(acl.Has(auth.ACE{Principal: "content-authors", Type: auth.ACETypeAllow, Privileges: []string{"jcr:read", "rep:write"}}))
+	a.True(acl.Has(auth.ACE{Principal: "everyone", Type: auth.ACETypeDeny, Privileges: []string{"jcr:all"}}))
+	a.Len(acl.ByPrincipal("everyone"), 1)
+}
+
+func TestACEValidate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.NoError(auth.ACE{Principal: "everyone", Type: auth.ACETypeAllow, Privileges: []string{"jcr:read"}}.Validate())
+	a.Error(auth.ACE{Principal: "everyone", Type: "grant", Privileges: []string{"jcr:read"}}.Validate())
+	a.Error(auth.ACE{Principal: "everyone", Type: auth.ACETypeDeny}.Validate())
+}
@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/wttech/aemc/pkg/auth"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ACL represents access control list defined on repository path
+type ACL struct {
+	instance *Instance
+	path     string
+}
+
+func (a ACL) Path() string {
+	return a.path
+}
+
+func (a ACL) policyPath() string {
+	return strings.TrimSuffix(a.path, "/") + "/rep:policy"
+}
+
+// Read returns entries defined directly on path (inherited ones are not included)
+func (a ACL) Read() (*auth.ACL, error) {
+	response, err := a.instance.http.Request().Get(a.policyPath() + ".2.json")
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot read ACL of path '%s': %w", a.instance.ID(), a.path, err)
+	} else if response.StatusCode() == http.StatusNotFound {
+		result := auth.ParseACL(a.path, map[string]any{})
+		return &result, nil
+	} else if response.IsError() {
+		return nil, fmt.Errorf("%s > cannot read ACL of path '%s': %s", a.instance.ID(), a.path, response.Status())
+	}
+	var policy map[string]any
+	if err = fmtx.UnmarshalJSON(response.RawBody(), &policy); err != nil {
+		return nil, fmt.Errorf("%s > cannot parse ACL of path '%s': %w", a.instance.ID(), a.path, err)
+	}
+	result := auth.ParseACL(a.path, policy)
+	return &result, nil
+}
+
+// Save adds or updates entry using Sling access manager (privileges are merged with existing ones of principal)
+func (a ACL) Save(ace auth.ACE) error {
+	if err := ace.Validate(); err != nil {
+		return fmt.Errorf("%s > cannot save ACE on path '%s': %w", a.instance.ID(), a.path, err)
+	}
+	log.Infof("%s > saving ACE '%s' on path '%s'", a.instance.ID(), ace.String(), a.path)
+	request := a.instance.http.Request()
+	request.FormData.Set("principalId", ace.Principal)
+	privilegeValue := "granted"
+	if ace.Type == auth.ACETypeDeny {
+		privilegeValue = "denied"
+	}
+	for _, privilege := range ace.Privileges {
+		request.FormData.Set("privilege@"+privilege, privilegeValue)
+	}
+	for name, value := range ace.Restrictions {
+		request.FormData.Set("restriction@"+name, value)
+	}
+	response, err := request.Post(a.path + ".modifyAce.html")
+	if err != nil {
+		return fmt.Errorf("%s > cannot save ACE '%s' on path '%s': %w", a.instance.ID(), ace.String(), a.path, err)
+	} else if response.IsError() {
+		return fmt.Errorf("%s > cannot save ACE '%s' on path '%s': %s", a.instance.ID(), ace.String(), a.path, response.Status())
+	}
+	log.Infof("%s > saved ACE '%s' on path '%s'", a.instance.ID(), ace.String(), a.path)
+	return nil
+}
+
+func (a ACL) SaveWithChanged(ace auth.ACE) (bool, error) {
+	before, err := a.Read()
+	if err != nil {
+		return false, err
+	}
+	if before.Has(ace) {
+		return false, nil
+	}
+	if err := a.Save(ace); err != nil {
+		return false, err
+	}
+	after, err := a.Read()
+	if err != nil {
+		return false, err
+	}
+	return !reflect.DeepEqual(before.ByPrincipal(ace.Principal), after.ByPrincipal(ace.Principal)), nil
+}
+
+// Delete removes all entries of principal
+func (a ACL) Delete(principal string) error {
+	log.Infof("%s > deleting ACEs of principal '%s' on path '%s'", a.instance.ID(), principal, a.path)
+	response, err := a.instance.http.Request().
+		SetFormData(map[string]string{":applyTo": principal}).
+		Post(a.path + ".deleteAce.html")
+	if err != nil {
+		return fmt.Errorf("%s > cannot delete ACEs of principal '%s' on path '%s': %w", a.instance.ID(), principal, a.path, err)
+	} else if response.IsError() {
+		return fmt.Errorf("%s > cannot delete ACEs of principal '%s' on path '%s': %s", a.instance.ID(), principal, a.path, response.Status())
+	}
+	log.Infof("%s > deleted ACEs of principal '%s' on path '%s'", a.instance.ID(), principal, a.path)
+	return nil
+}
+
+func (a ACL) DeleteWithChanged(principal string) (bool, error) {
+	acl, err := a.Read()
+	if err != nil {
+		return false, err
+	}
+	if len(acl.ByPrincipal(principal)) == 0 {
+		return false, nil
+	}
+	if err := a.Delete(principal); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a ACL) MarshalJSON() ([]byte, error) {
+	acl, err := a.Read()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(acl)
+}
+
+func (a ACL) MarshalYAML() (interface{}, error) {
+	return a.Read()
+}
+
+func (a ACL) MarshalText() string {
+	acl, err := a.Read()
+	if err != nil {
+		return fmt.Sprintf("ACL of path '%s' cannot be read\n", a.path)
+	}
+	return acl.MarshalText()
+}
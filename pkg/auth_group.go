@@ -0,0 +1,163 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/auth"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+)
+
+type Group struct {
+	manager *GroupManager
+	id      string
+}
+
+func (g Group) ID() string {
+	return g.id
+}
+
+type GroupState struct {
+	data *auth.Authorizable
+
+	ID      string   `yaml:"id" json:"id"`
+	Exists  bool     `yaml:"exists" json:"exists"`
+	Path    string   `yaml:"path,omitempty" json:"path,omitempty"`
+	Members []string `yaml:"members,omitempty" json:"members,omitempty"`
+}
+
+func (g Group) State() (*GroupState, error) {
+	data, err := g.manager.Find(g.id)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return &GroupState{
+			ID:     g.id,
+			Exists: false,
+		}, nil
+	}
+	members, err := g.manager.Members(g.id)
+	if err != nil {
+		return nil, err
+	}
+	return &GroupState{
+		data: data,
+
+		ID:      g.id,
+		Exists:  true,
+		Path:    data.Path,
+		Members: members,
+	}, nil
+}
+
+func (g Group) Create(intermediatePath string) error {
+	return g.manager.Create(g.id, intermediatePath)
+}
+
+func (g Group) CreateWithChanged(intermediatePath string) (bool, error) {
+	data, err := g.manager.Find(g.id)
+	if err != nil {
+		return false, err
+	}
+	if data != nil {
+		return false, nil
+	}
+	if err := g.manager.Create(g.id, intermediatePath); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (g Group) Delete() error {
+	return g.manager.Delete(g.id)
+}
+
+func (g Group) DeleteWithChanged() (bool, error) {
+	data, err := g.manager.Find(g.id)
+	if err != nil {
+		return false, err
+	}
+	if data == nil {
+		return false, nil
+	}
+	if err := g.manager.Delete(g.id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (g Group) Members() ([]string, error) {
+	return g.manager.Members(g.id)
+}
+
+func (g Group) AddMembers(members []string) error {
+	return g.manager.AddMembers(g.id, members)
+}
+
+// AddMembersWithChanged adds only users or groups which are not yet members
+func (g Group) AddMembersWithChanged(members []string) (bool, error) {
+	current, err := g.manager.Members(g.id)
+	if err != nil {
+		return false, err
+	}
+	missing := lo.Without(lo.Uniq(members), current...)
+	if len(missing) == 0 {
+		return false, nil
+	}
+	if err := g.manager.AddMembers(g.id, missing); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (g Group) RemoveMembers(members []string) error {
+	return g.manager.RemoveMembers(g.id, members)
+}
+
+// RemoveMembersWithChanged removes only users or groups which are members
+func (g Group) RemoveMembersWithChanged(members []string) (bool, error) {
+	current, err := g.manager.Members(g.id)
+	if err != nil {
+		return false, err
+	}
+	existing := lo.Intersect(current, lo.Uniq(members))
+	if len(existing) == 0 {
+		return false, nil
+	}
+	if err := g.manager.RemoveMembers(g.id, existing); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (g Group) MarshalJSON() ([]byte, error) {
+	state, err := g.State()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(state)
+}
+
+func (g Group) MarshalYAML() (interface{}, error) {
+	return g.State()
+}
+
+func (g Group) MarshalText() string {
+	state, err := g.State()
+	if err != nil {
+		return fmt.Sprintf("group '%s' state cannot be read\n", g.id)
+	}
+	sb := bytes.NewBufferString("")
+	if state.Exists {
+		sb.WriteString(fmt.Sprintf("group '%s'\n", g.id))
+		sb.WriteString(fmtx.TblMap("details", "name", "value", map[string]any{
+			"path":    state.Path,
+			"members": state.Members,
+		}))
+	} else {
+		sb.WriteString(fmt.Sprintf("group '%s' cannot be found\n", g.id))
+	}
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/wttech/aemc/pkg/auth"
+	"strings"
+)
+
+type GroupManager struct {
+	instance *Instance
+}
+
+func (gm *GroupManager) ByID(id string) Group {
+	return Group{manager: gm, id: id}
+}
+
+func (gm *GroupManager) Find(id string) (*auth.Authorizable, error) {
+	authorizable, err := findAuthorizable(gm.instance, id)
+	if err != nil {
+		return nil, err
+	}
+	if authorizable != nil && !authorizable.IsGroup() {
+		return nil, fmt.Errorf("%s > authorizable '%s' is a user, not a group", gm.instance.ID(), id)
+	}
+	return authorizable, nil
+}
+
+func (gm *GroupManager) Create(id string, intermediatePath string) error {
+	log.Infof("%s > creating group '%s'", gm.instance.ID(), id)
+	params := map[string]string{"createGroup": "", "authorizableId": id}
+	if intermediatePath != "" {
+		params["intermediatePath"] = intermediatePath
+	}
+	if err := postAuthorizable(gm.instance, AuthorizablesPostPath, fmt.Sprintf("create group '%s'", id), params); err != nil {
+		return err
+	}
+	log.Infof("%s > created group '%s'", gm.instance.ID(), id)
+	return nil
+}
+
+func (gm *GroupManager) Delete(id string) error {
+	authorizable, err := gm.requireFound(id, "deleted")
+	if err != nil {
+		return err
+	}
+	log.Infof("%s > deleting group '%s'", gm.instance.ID(), id)
+	if err := postAuthorizable(gm.instance, authorizable.Path+".rw.html", fmt.Sprintf("delete group '%s'", id), map[string]string{"deleteAuthorizable": ""}); err != nil {
+		return err
+	}
+	log.Infof("%s > deleted group '%s'", gm.instance.ID(), id)
+	return nil
+}
+
+// Members returns IDs of users and groups declared as group members
+func (gm *GroupManager) Members(id string) ([]string, error) {
+	return authorizableMembers(gm.instance, fmt.Sprintf("%s/%s.json", UserManagerGroupPath, id), "declaredMembers")
+}
+
+func (gm *GroupManager) AddMembers(id string, members []string) error {
+	return gm.updateMembers(id, members, "addMembers", "adding", "added")
+}
+
+func (gm *GroupManager) RemoveMembers(id string, members []string) error {
+	return gm.updateMembers(id, members, "removeMembers", "removing", "removed")
+}
+
+func (gm *GroupManager) updateMembers(id string, members []string, param string, verbDoing string, verbDone string) error {
+	authorizable, err := gm.requireFound(id, "updated")
+	if err != nil {
+		return err
+	}
+	log.Infof("%s > %s members '%s' of group '%s'", gm.instance.ID(), verbDoing, strings.Join(members, ", "), id)
+	request := gm.instance.http.Request()
+	for _, member := range members {
+		request.FormData.Add(param, member)
+	}
+	response, err := request.Post(authorizable.Path + ".rw.html")
+	if err != nil {
+		return fmt.Errorf("%s > cannot update members of group '%s': %w", gm.instance.ID(), id, err)
+	} else if response.IsError() {
+		return fmt.Errorf("%s > cannot update members of group '%s': %s", gm.instance.ID(), id, response.Status())
+	}
+	log.Infof("%s > %s members '%s' of group '%s'", gm.instance.ID(), verbDone, strings.Join(members, ", "), id)
+	return nil
+}
+
+func (gm *GroupManager) requireFound(id string, action string) (*auth.Authorizable, error) {
+	authorizable, err := gm.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if authorizable == nil {
+		return nil, fmt.Errorf("%s > group '%s' cannot be %s as it does not exist", gm.instance.ID(), id, action)
+	}
+	return authorizable, nil
+}
@@ -0,0 +1,164 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/wttech/aemc/pkg/auth"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+)
+
+type User struct {
+	manager *UserManager
+	id      string
+}
+
+func (u User) ID() string {
+	return u.id
+}
+
+type UserState struct {
+	data *auth.Authorizable
+
+	ID       string   `yaml:"id" json:"id"`
+	Exists   bool     `yaml:"exists" json:"exists"`
+	Path     string   `yaml:"path,omitempty" json:"path,omitempty"`
+	System   bool     `yaml:"system" json:"system"`
+	MemberOf []string `yaml:"member_of,omitempty" json:"memberOf,omitempty"`
+}
+
+func (u User) State() (*UserState, error) {
+	data, err := u.manager.Find(u.id)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return &UserState{
+			ID:     u.id,
+			Exists: false,
+		}, nil
+	}
+	memberOf, err := u.manager.MemberOf(u.id)
+	if err != nil {
+		return nil, err
+	}
+	return &UserState{
+		data: data,
+
+		ID:       u.id,
+		Exists:   true,
+		Path:     data.Path,
+		System:   data.IsSystemUser(),
+		MemberOf: memberOf,
+	}, nil
+}
+
+func (u User) Create(password string, system bool, intermediatePath string) error {
+	return u.manager.Create(u.id, password, system, intermediatePath)
+}
+
+// CreateWithChanged creates user if it does not exist; password of existing user is not changed (use 'ChangePasswordWithChanged')
+func (u User) CreateWithChanged(password string, system bool, intermediatePath string) (bool, error) {
+	data, err := u.manager.Find(u.id)
+	if err != nil {
+		return false, err
+	}
+	if data != nil {
+		if data.IsSystemUser() != system {
+			return false, fmt.Errorf("%s > user '%s' already exists but system flag differs (expected: %t)", u.manager.instance.ID(), u.id, system)
+		}
+		return false, nil
+	}
+	if err := u.manager.Create(u.id, password, system, intermediatePath); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Profile returns node holding user properties like 'email', 'givenName' or 'familyName'
+func (u User) Profile() (*RepoNode, error) {
+	data, err := u.manager.Find(u.id)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("%s > profile of user '%s' cannot be read as user does not exist", u.manager.instance.ID(), u.id)
+	}
+	node := u.manager.instance.repo.Node(data.Path + "/profile")
+	return &node, nil
+}
+
+func (u User) SaveProfileWithChanged(props map[string]any) (bool, error) {
+	profile, err := u.Profile()
+	if err != nil {
+		return false, err
+	}
+	return profile.SaveWithChanged(props)
+}
+
+func (u User) ChangePassword(password string) error {
+	return u.manager.ChangePassword(u.id, password)
+}
+
+func (u User) ChangePasswordWithChanged(password string) (bool, error) {
+	matches, err := u.manager.PasswordMatches(u.id, password)
+	if err != nil {
+		return false, err
+	}
+	if matches {
+		return false, nil
+	}
+	if err := u.manager.ChangePassword(u.id, password); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (u User) Delete() error {
+	return u.manager.Delete(u.id)
+}
+
+func (u User) DeleteWithChanged() (bool, error) {
+	data, err := u.manager.Find(u.id)
+	if err != nil {
+		return false, err
+	}
+	if data == nil {
+		return false, nil
+	}
+	if err := u.manager.Delete(u.id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (u User) MarshalJSON() ([]byte, error) {
+	state, err := u.State()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(state)
+}
+
+func (u User) MarshalYAML() (interface{}, error) {
+	return u.State()
+}
+
+func (u User) MarshalText() string {
+	state, err := u.State()
+	if err != nil {
+		return fmt.Sprintf("user '%s' state cannot be read\n", u.id)
+	}
+	sb := bytes.NewBufferString("")
+	if state.Exists {
+		sb.WriteString(fmt.Sprintf("user '%s'\n", u.id))
+		sb.WriteString(fmtx.TblMap("details", "name", "value", map[string]any{
+			"path":      state.Path,
+			"system":    state.System,
+			"member of": state.MemberOf,
+		}))
+	} else {
+		sb.WriteString(fmt.Sprintf("user '%s' cannot be found\n", u.id))
+	}
+	return sb.String()
+}
@@ -0,0 +1,115 @@
+package pkg
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/wttech/aemc/pkg/auth"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"net/http"
+)
+
+type UserManager struct {
+	instance *Instance
+}
+
+func (um *UserManager) ByID(id string) User {
+	return User{manager: um, id: id}
+}
+
+func (um *UserManager) Find(id string) (*auth.Authorizable, error) {
+	authorizable, err := findAuthorizable(um.instance, id)
+	if err != nil {
+		return nil, err
+	}
+	if authorizable != nil && authorizable.IsGroup() {
+		return nil, fmt.Errorf("%s > authorizable '%s' is a group, not a user", um.instance.ID(), id)
+	}
+	return authorizable, nil
+}
+
+// Create creates regular user (with password) or system user (without password, used by services)
+func (um *UserManager) Create(id string, password string, system bool, intermediatePath string) error {
+	log.Infof("%s > creating user '%s'", um.instance.ID(), id)
+	params := map[string]string{"authorizableId": id}
+	if system {
+		params["createSystemUser"] = ""
+	} else {
+		if password == "" {
+			return fmt.Errorf("%s > cannot create user '%s' as password is not specified", um.instance.ID(), id)
+		}
+		params["createUser"] = ""
+		params["rep:password"] = password
+	}
+	if intermediatePath != "" {
+		params["intermediatePath"] = intermediatePath
+	}
+	if err := postAuthorizable(um.instance, AuthorizablesPostPath, fmt.Sprintf("create user '%s'", id), params); err != nil {
+		return err
+	}
+	log.Infof("%s > created user '%s'", um.instance.ID(), id)
+	return nil
+}
+
+func (um *UserManager) Delete(id string) error {
+	authorizable, err := um.Find(id)
+	if err != nil {
+		return err
+	}
+	if authorizable == nil {
+		return fmt.Errorf("%s > user '%s' cannot be deleted as it does not exist", um.instance.ID(), id)
+	}
+	log.Infof("%s > deleting user '%s'", um.instance.ID(), id)
+	if err := postAuthorizable(um.instance, authorizable.Path+".rw.html", fmt.Sprintf("delete user '%s'", id), map[string]string{"deleteAuthorizable": ""}); err != nil {
+		return err
+	}
+	log.Infof("%s > deleted user '%s'", um.instance.ID(), id)
+	return nil
+}
+
+func (um *UserManager) ChangePassword(id string, password string) error {
+	authorizable, err := um.Find(id)
+	if err != nil {
+		return err
+	}
+	if authorizable == nil {
+		return fmt.Errorf("%s > password of user '%s' cannot be changed as user does not exist", um.instance.ID(), id)
+	}
+	if authorizable.IsSystemUser() {
+		return fmt.Errorf("%s > password of user '%s' cannot be changed as it is a system user", um.instance.ID(), id)
+	}
+	log.Infof("%s > changing password of user '%s'", um.instance.ID(), id)
+	if err := postAuthorizable(um.instance, authorizable.Path+".rw.html", fmt.Sprintf("change password of user '%s'", id), map[string]string{"rep:password": password}); err != nil {
+		return err
+	}
+	if id == um.instance.User() {
+		log.Warnf("%s > changed password of user '%s' used to connect to instance; update instance configuration accordingly", um.instance.ID(), id)
+	} else {
+		log.Infof("%s > changed password of user '%s'", um.instance.ID(), id)
+	}
+	return nil
+}
+
+// PasswordMatches checks if user is able to log in with password
+func (um *UserManager) PasswordMatches(id string, password string) (bool, error) {
+	response, err := um.instance.http.Request().SetBasicAuth(id, password).Get(AuthorizableCurrentPath)
+	if err != nil {
+		return false, fmt.Errorf("%s > cannot check password of user '%s': %w", um.instance.ID(), id, err)
+	}
+	switch response.StatusCode() {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false, nil
+	case http.StatusOK:
+		var current map[string]any
+		if err = fmtx.UnmarshalJSON(response.RawBody(), &current); err != nil {
+			return false, fmt.Errorf("%s > cannot parse current user while checking password of user '%s': %w", um.instance.ID(), id, err)
+		}
+		return current["authorizableId"] == id, nil
+	default:
+		return false, fmt.Errorf("%s > cannot check password of user '%s': %s", um.instance.ID(), id, response.Status())
+	}
+}
+
+// MemberOf returns IDs of groups which user is declared member of
+func (um *UserManager) MemberOf(id string) ([]string, error) {
+	return authorizableMembers(um.instance, fmt.Sprintf("%s/%s.json", UserManagerUserPath, id), "declaredMemberOf")
+}
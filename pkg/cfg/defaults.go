@@ -34,6 +34,8 @@ func (c *Config) setDefaults() {
 	v.SetDefault("java.download.url", c.tplString("https://github.com/adoptium/temurin11-binaries/releases/download/jdk-11.0.18%2B10/OpenJDK11U-jdk_[[.Arch]]_[[.Os]]_hotspot_11.0.18_10.[[.ArchiveExt]]"))
 	v.SetDefault("java.download.replacements", map[string]string{"darwin": "mac", "x86_64": "x64", "amd64": "x64", "386": "x86-32", "arm64": "x64", "aarch64": "x64"})
 
+	v.SetDefault("maven.repositories", []string{"~/.m2/repository", "https://repo1.maven.org/maven2"})
+
 	v.SetDefault("instance.processing_mode", instance.ProcessingAuto)
 
 	v.SetDefault("instance.http.timeout", time.Minute*5)
@@ -52,12 +54,16 @@ func (c *Config) setDefaults() {
 
 	v.SetDefault("instance.check.reachable.timeout", time.Second*3)
 
+	v.SetDefault("instance.check.bundle_stable.diagnose", true)
+
 	v.SetDefault("instance.check.event_stable.received_max_age", time.Second*5)
 	v.SetDefault("instance.check.event_stable.topics_unstable", []string{"org/osgi/framework/ServiceEvent/*", "org/osgi/framework/FrameworkEvent/*", "org/osgi/framework/BundleEvent/*"})
 	v.SetDefault("instance.check.event_stable.details_ignored", []string{"*.*MBean", "org.osgi.service.component.runtime.ServiceComponentRuntime", "java.util.ResourceBundle"})
 
 	v.SetDefault("instance.check.installer.state", true)
 	v.SetDefault("instance.check.installer.pause", true)
+	v.SetDefault("instance.check.replication_queue.enabled", false)
+	v.SetDefault("instance.check.replication_queue.agent_names", []string{"publish"})
 
 	v.SetDefault("instance.local.tool_dir", common.ToolDir)
 	v.SetDefault("instance.local.unpack_dir", common.VarDir+"/instance")
@@ -77,8 +83,14 @@ func (c *Config) setDefaults() {
 	v.SetDefault("instance.package.snapshot_deploy_skipping", true)
 	v.SetDefault("instance.package.snapshot_patterns", []string{"**/*-SNAPSHOT.zip"})
 	v.SetDefault("instance.package.toggled_workflows", []string{})
+	v.SetDefault("instance.package.install.recursive", true)
+	v.SetDefault("instance.package.install.ac_handling", "")
+	v.SetDefault("instance.package.install.autosave", 1024)
+	v.SetDefault("instance.package.install.dependency_handling", "")
 
 	v.SetDefault("instance.repo.property_change_ignored", []string{"jcr:created", "cq:lastModified", "transportPassword"})
+	v.SetDefault("instance.repo.traversal.concurrency", 4)
+	v.SetDefault("instance.repo.traversal.batch_depth", 2)
 
 	v.SetDefault("instance.osgi.shutdown_delay", time.Second*3)
 	v.SetDefault("instance.osgi.bundle.install.start", true)
@@ -91,4 +103,8 @@ func (c *Config) setDefaults() {
 	v.SetDefault("instance.workflow.config_root", "/conf/global/settings/workflow/launcher")
 	v.SetDefault("instance.workflow.toggle_retry_delay", time.Second*10)
 	v.SetDefault("instance.workflow.toggle_retry_timeout", time.Minute*5)
+
+	v.SetDefault("instance.replication.agent", "publish")
+	v.SetDefault("instance.replication.queue.timeout", time.Minute*5)
+	v.SetDefault("instance.replication.queue.interval", time.Second*2)
 }
@@ -3,12 +3,14 @@ package pkg
 import (
 	"fmt"
 	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
 	"github.com/wttech/aemc/pkg/common/lox"
 	"github.com/wttech/aemc/pkg/common/netx"
 	"github.com/wttech/aemc/pkg/common/stringsx"
 	"github.com/wttech/aemc/pkg/osgi"
 	"io"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -89,6 +91,9 @@ func NewBundleStableChecker(opts *CheckOpts) BundleStableChecker {
 
 	return BundleStableChecker{
 		SymbolicNamesIgnored: cv.GetStringSlice("instance.check.bundle_stable.symbolic_names_ignored"),
+		Diagnose:             cv.GetBool("instance.check.bundle_stable.diagnose"),
+
+		diagnoses: &bundleDiagnoses{entries: map[string]bundleDiagnosisEntry{}},
 	}
 }
 
@@ -98,6 +103,21 @@ func (c BundleStableChecker) Spec() CheckSpec {
 
 type BundleStableChecker struct {
 	SymbolicNamesIgnored []string
+	Diagnose             bool
+
+	diagnoses *bundleDiagnoses
+}
+
+// bundleDiagnoses remembers diagnosis per instance so that exporters are looked up only once for the same unstable bundles
+type bundleDiagnoses struct {
+	mutex   sync.Mutex
+	entries map[string]bundleDiagnosisEntry
+}
+
+type bundleDiagnosisEntry struct {
+	unstable  string
+	done      bool
+	diagnosis *osgi.BundleDiagnosis
 }
 
 func (c BundleStableChecker) Check(instance Instance) CheckResult {
@@ -117,6 +137,11 @@ func (c BundleStableChecker) Check(instance Instance) CheckResult {
 		randomBundleSymbolicName := lox.Random(unstableBundles).SymbolicName
 		if unstableBundleCount <= 10 {
 			message = fmt.Sprintf("some bundles unstable (%d): '%s'", unstableBundleCount, randomBundleSymbolicName)
+			if c.Diagnose {
+				if diagnosis := c.diagnose(instance, unstableBundles); diagnosis != nil {
+					message = fmt.Sprintf("some bundles unstable (%d): %s", unstableBundleCount, diagnosis.Summary())
+				}
+			}
 		} else {
 			message = fmt.Sprintf("many bundles unstable (%s): '%s'", bundleStablePercent(bundles, unstableBundles), randomBundleSymbolicName)
 		}
@@ -131,6 +156,34 @@ func (c BundleStableChecker) Check(instance Instance) CheckResult {
 	}
 }
 
+// diagnose is performed only when the same bundles remain unstable between checks, then its result is reused until they change
+func (c BundleStableChecker) diagnose(instance Instance, unstableBundles []osgi.BundleListItem) *osgi.BundleDiagnosis {
+	unstable := strings.Join(lo.Map(unstableBundles, func(b osgi.BundleListItem, _ int) string { return fmt.Sprintf("%d:%d", b.ID, b.StateRaw) }), ",")
+	c.diagnoses.mutex.Lock()
+	entry := c.diagnoses.entries[instance.ID()]
+	if entry.unstable != unstable {
+		c.diagnoses.entries[instance.ID()] = bundleDiagnosisEntry{unstable: unstable}
+	}
+	c.diagnoses.mutex.Unlock()
+	if entry.unstable != unstable {
+		return nil
+	}
+	if entry.done {
+		return entry.diagnosis
+	}
+	diagnosis, err := instance.osgi.bundleManager.Diagnose()
+	if err != nil {
+		log.Debugf("%s > cannot diagnose unstable bundles: %s", instance.ID(), err)
+		diagnosis = nil
+	} else if diagnosis.Empty() {
+		diagnosis = nil
+	}
+	c.diagnoses.mutex.Lock()
+	c.diagnoses.entries[instance.ID()] = bundleDiagnosisEntry{unstable: unstable, done: true, diagnosis: diagnosis}
+	c.diagnoses.mutex.Unlock()
+	return diagnosis
+}
+
 type EventStableChecker struct {
 	ReceivedMaxAge time.Duration
 	TopicsUnstable []string
@@ -181,6 +234,60 @@ func (c EventStableChecker) Check(instance Instance) CheckResult {
 	}
 }
 
+func NewReplicationQueueChecker(opts *CheckOpts) ReplicationQueueChecker {
+	cv := opts.manager.aem.config.Values()
+
+	return ReplicationQueueChecker{
+		Enabled:    cv.GetBool("instance.check.replication_queue.enabled"),
+		AgentNames: cv.GetStringSlice("instance.check.replication_queue.agent_names"),
+	}
+}
+
+// ReplicationQueueChecker waits until queues of replication agents on author instances are empty
+type ReplicationQueueChecker struct {
+	Enabled    bool
+	AgentNames []string
+}
+
+func (c ReplicationQueueChecker) Spec() CheckSpec {
+	return CheckSpec{Mandatory: false}
+}
+
+func (c ReplicationQueueChecker) Check(instance Instance) CheckResult {
+	if !instance.IsAuthor() {
+		return CheckResult{
+			ok:      true,
+			message: "replication queues not applicable",
+		}
+	}
+	for _, agentName := range c.AgentNames {
+		queue, err := instance.repo.ReplAgent(ReplicationAgentsAuthor, agentName).Queue()
+		if err != nil {
+			return CheckResult{
+				ok:      false,
+				message: fmt.Sprintf("replication queue '%s' unknown", agentName),
+				err:     err,
+			}
+		}
+		if queue.MetaData.Status.Blocked {
+			return CheckResult{
+				ok:      false,
+				message: fmt.Sprintf("replication queue '%s' blocked (%d): %s", agentName, len(queue.Items), queue.LastError()),
+			}
+		}
+		if !queue.Empty() {
+			return CheckResult{
+				ok:      false,
+				message: fmt.Sprintf("replication queue '%s' not empty (%d)", agentName, len(queue.Items)),
+			}
+		}
+	}
+	return CheckResult{
+		ok:      true,
+		message: "replication queues empty",
+	}
+}
+
 func NewInstallerChecker(opts *CheckOpts) InstallerChecker {
 	cv := opts.manager.aem.config.Values()
 
@@ -32,6 +32,25 @@ func WriteString(path string, text string) error {
 	return Write(path, []byte(text))
 }
 
+func WriteReader(path string, reader io.Reader) error {
+	err := pathx.Ensure(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create file '%s': %w", path, err)
+	}
+	if _, err = io.Copy(file, reader); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("cannot write to file '%s': %w", path, err)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("cannot close file '%s': %w", path, err)
+	}
+	return nil
+}
+
 func Read(path string) ([]byte, error) {
 	if !pathx.Exists(path) {
 		return nil, fmt.Errorf("cannot read file as it does not exist at path '%s'", path)
@@ -158,6 +177,14 @@ func ChecksumFile(file string) (string, error) {
 	return checksum.MD5sum(file)
 }
 
+func ChecksumReader(reader io.Reader) (string, error) {
+	hash := md5.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", fmt.Errorf("cannot read data to calculate checksum: %w", err)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
 func ChecksumDir(dir string, pathIgnored []string) (string, error) {
 	hash := md5.New()
 	pathIgnoreMatcher := pathx.NewIgnoreMatcher(pathIgnored)
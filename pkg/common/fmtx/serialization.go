@@ -1,7 +1,9 @@
 package fmtx
 
 import (
+	"bytes"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"github.com/wttech/aemc/pkg/common/filex"
@@ -133,3 +135,10 @@ func MarshalText(value any) string {
 	}
 	return result
 }
+
+// EscapeXML makes value safe to be used in XML text or attribute value
+func EscapeXML(value string) string {
+	bs := bytes.NewBufferString("")
+	_ = xml.EscapeText(bs, []byte(value))
+	return bs.String()
+}
@@ -32,6 +32,26 @@ func ParallelMap[I any, R any](iterable []I, callback func(iteratee I) (R, error
 	return results, err
 }
 
+// LimitedMap is like ParallelMap but runs at most given number of callbacks at once
+func LimitedMap[I any, R any](limit int, iterable []I, callback func(iteratee I) (R, error)) ([]R, error) {
+	g, _ := errgroup.WithContext(context.Background())
+	g.SetLimit(limit)
+	results := make([]R, len(iterable))
+	for i, iteratee := range iterable {
+		i, iteratee := i, iteratee
+		g.Go(func() error {
+			result, err := callback(iteratee)
+			if err != nil {
+				return err
+			}
+			results[i] = result
+			return nil
+		})
+	}
+	err := g.Wait()
+	return results, err
+}
+
 func SerialMap[I any, R any](iterable []I, callback func(iteratee I) (R, error)) ([]R, error) {
 	results := make([]R, len(iterable))
 	for i, iteratee := range iterable {
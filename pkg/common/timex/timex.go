@@ -1,6 +1,9 @@
 package timex
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,3 +18,38 @@ func FileTimestampForNow() string {
 func Human(time time.Time) string {
 	return time.Format("2006-01-02 15:04:05")
 }
+
+var parseLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// Parse reads time in one of commonly used formats (local time zone is assumed when missing)
+func Parse(value string) (time.Time, error) {
+	for _, layout := range parseLayouts {
+		result, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return result, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse time '%s' (expected format like '2006-01-02' or '2006-01-02 15:04:05')", value)
+}
+
+// ParseDuration is like time.ParseDuration but also supports days (e.g. '30d' or '1d12h')
+func ParseDuration(value string) (time.Duration, error) {
+	days := 0
+	if before, after, ok := strings.Cut(value, "d"); ok {
+		count, err := strconv.Atoi(before)
+		if err != nil {
+			return 0, fmt.Errorf("cannot parse duration '%s': %w", value, err)
+		}
+		days = count
+		value = after
+	}
+	result := time.Duration(days) * 24 * time.Hour
+	if value != "" {
+		rest, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("cannot parse duration '%s': %w", value, err)
+		}
+		result += rest
+	}
+	return result, nil
+}
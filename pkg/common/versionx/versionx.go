@@ -0,0 +1,76 @@
+package versionx
+
+import (
+	"github.com/hashicorp/go-version"
+	"strings"
+)
+
+// Parse reads version leniently; OSGi qualifiers (e.g. '1.2.0.SNAPSHOT') are ignored when version cannot be read as is
+func Parse(value string) (*version.Version, error) {
+	value = strings.Trim(strings.TrimSpace(value), "\"")
+	result, err := version.NewVersion(value)
+	if err == nil {
+		return result, nil
+	}
+	parts := strings.SplitN(value, ".", 4)
+	if len(parts) == 4 {
+		if qualified, qualifiedErr := version.NewVersion(strings.Join(parts[:3], ".")); qualifiedErr == nil {
+			return qualified, nil
+		}
+	}
+	return nil, err
+}
+
+// Less compares versions; versions which cannot be parsed are never less
+func Less(value string, other string) bool {
+	valueVersion, err := Parse(value)
+	if err != nil {
+		return false
+	}
+	otherVersion, err := Parse(other)
+	if err != nil {
+		return false
+	}
+	return valueVersion.LessThan(otherVersion)
+}
+
+// InRange checks version against range in format used by OSGi and FileVault (e.g. '[1.0,2.0)', '(,1.5]' or '1.0' meaning at least '1.0')
+func InRange(value string, versionRange string) bool {
+	versionRange = strings.Trim(strings.TrimSpace(versionRange), "\"")
+	if versionRange == "" {
+		return true
+	}
+	current, err := Parse(value)
+	if err != nil {
+		return value == versionRange
+	}
+	if !strings.HasPrefix(versionRange, "[") && !strings.HasPrefix(versionRange, "(") {
+		minimal, err := Parse(versionRange)
+		if err != nil {
+			return value == versionRange
+		}
+		return current.GreaterThanOrEqual(minimal)
+	}
+	lowerInclusive := strings.HasPrefix(versionRange, "[")
+	upperInclusive := strings.HasSuffix(versionRange, "]")
+	lower, upper, _ := strings.Cut(strings.Trim(versionRange, "[]()"), ",")
+	if lower = strings.TrimSpace(lower); lower != "" {
+		lowerVersion, err := Parse(lower)
+		if err != nil {
+			return false
+		}
+		if current.LessThan(lowerVersion) || (!lowerInclusive && current.Equal(lowerVersion)) {
+			return false
+		}
+	}
+	if upper = strings.TrimSpace(upper); upper != "" {
+		upperVersion, err := Parse(upper)
+		if err != nil {
+			return false
+		}
+		if current.GreaterThan(upperVersion) || (!upperInclusive && current.Equal(upperVersion)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package versionx_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/common/versionx"
+	"testing"
+)
+
+func TestInRange(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.True(versionx.InRange("1.0.0", ""))
+	a.True(versionx.InRange("1.2.0", "1.0.0"))
+	a.False(versionx.InRange("0.9.0", "1.0.0"))
+	a.True(versionx.InRange("6.5.0", "[6.5.0,7)"))
+	a.False(versionx.InRange("7.0.0", "[6.5.0,7)"))
+	a.False(versionx.InRange("1.0.0", "(1.0.0,]"))
+	a.True(versionx.InRange("1.5", "(,1.5]"))
+	a.True(versionx.InRange("2.9.1", "[2.9,3)"))
+	a.False(versionx.InRange("2.8.9", "[2.9,3)"))
+	a.True(versionx.InRange("3.0.0", "\"[2.9,3.0.0]\""))
+	a.False(versionx.InRange("2.9.0", "(2.9,3)"))
+	a.True(versionx.InRange("1.2.0.SNAPSHOT", "1.2"))
+}
+
+func TestLess(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.True(versionx.Less("1.9.0", "1.10.0"))
+	a.False(versionx.Less("1.10.0", "1.9.0"))
+	a.False(versionx.Less("snapshot", "1.0.0"))
+}
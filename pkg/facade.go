@@ -5,6 +5,7 @@ import (
 	"github.com/wttech/aemc/pkg/base"
 	"github.com/wttech/aemc/pkg/cfg"
 	"github.com/wttech/aemc/pkg/java"
+	"github.com/wttech/aemc/pkg/maven"
 	"github.com/wttech/aemc/pkg/project"
 	"io"
 	"os"
@@ -18,6 +19,7 @@ type AEM struct {
 	project         *project.Project
 	baseOpts        *base.Opts
 	javaOpts        *java.Opts
+	mavenOpts       *maven.Opts
 	instanceManager *InstanceManager
 }
 
@@ -32,6 +34,7 @@ func NewAEM(config *cfg.Config) *AEM {
 	result.project = project.New(result.config)
 	result.baseOpts = base.NewOpts(result.config)
 	result.javaOpts = java.NewOpts(result.baseOpts)
+	result.mavenOpts = maven.NewOpts(result.baseOpts)
 	result.instanceManager = NewInstanceManager(result)
 	return result
 }
@@ -61,6 +64,10 @@ func (a *AEM) JavaOpts() *java.Opts {
 	return a.javaOpts
 }
 
+func (a *AEM) MavenOpts() *maven.Opts {
+	return a.mavenOpts
+}
+
 func (a *AEM) InstanceManager() *InstanceManager {
 	return a.instanceManager
 }
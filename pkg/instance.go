@@ -7,6 +7,7 @@ import (
 	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 	"github.com/wttech/aemc/pkg/common/fmtx"
+	"github.com/wttech/aemc/pkg/common/pathx"
 	"github.com/wttech/aemc/pkg/instance"
 	"golang.org/x/exp/maps"
 	nurl "net/url"
@@ -28,6 +29,8 @@ type Instance struct {
 	osgi            *OSGi
 	sling           *Sling
 	crypto          *Crypto
+	auth            *Auth
+	replication     *Replication
 	packageManager  *PackageManager
 	workflowManager *WorkflowManager
 }
@@ -104,6 +107,14 @@ func (i Instance) Crypto() *Crypto {
 	return i.crypto
 }
 
+func (i Instance) Auth() *Auth {
+	return i.auth
+}
+
+func (i Instance) Replication() *Replication {
+	return i.replication
+}
+
 func (i Instance) IDInfo() IDInfo {
 	parts := strings.Split(i.id, instance.IDDelimiter)
 	if len(parts) == 2 {
@@ -133,6 +144,14 @@ func (i Instance) IsRemote() bool {
 	return !i.IsLocal()
 }
 
+// LockDir returns directory for storing instance state files (for remote instances it is located in the project's AEM home)
+func (i Instance) LockDir() string {
+	if i.IsLocal() {
+		return i.local.LockDir()
+	}
+	return pathx.Canonical(fmt.Sprintf("%s/%s/lock", RemoteDir, i.ID()))
+}
+
 func (i Instance) IsAuthor() bool {
 	return i.IDInfo().Role == instance.RoleAuthor
 }
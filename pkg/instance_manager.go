@@ -81,6 +81,15 @@ func (im *InstanceManager) All() []Instance {
 	return im.filter(result)
 }
 
+// ByID finds instance by ID regardless of current filters
+func (im *InstanceManager) ByID(id string) (*Instance, error) {
+	instance, ok := lo.Find(im.newAdHocOrFromConfig(), func(i Instance) bool { return i.id == id })
+	if !ok {
+		return nil, fmt.Errorf("instance with ID '%s' is not defined", id)
+	}
+	return &instance, nil
+}
+
 func (im *InstanceManager) newAdHocOrFromConfig() []Instance {
 	if im.AdHocURL != "" {
 		iURL, err := im.NewByURL(im.AdHocURL)
@@ -248,6 +257,8 @@ func (im *InstanceManager) New(id, url, user, password string) *Instance {
 	res.osgi = NewOSGi(res)
 	res.sling = NewSling(res)
 	res.crypto = NewCrypto(res)
+	res.auth = NewAuth(res)
+	res.replication = NewReplication(res)
 
 	if res.IsLocal() {
 		res.local = NewLocal(res)
@@ -20,6 +20,7 @@ type CheckOpts struct {
 	BundleStable  BundleStableChecker
 	EventStable   EventStableChecker
 	Installer     InstallerChecker
+	ReplQueue     ReplicationQueueChecker
 	AwaitStarted  AwaitChecker
 	Unreachable   ReachableHTTPChecker
 	StatusStopped StatusStoppedChecker
@@ -42,6 +43,7 @@ func NewCheckOpts(manager *InstanceManager) *CheckOpts {
 	result.EventStable = NewEventStableChecker(result)
 	result.AwaitStarted = NewAwaitChecker(result, "started")
 	result.Installer = NewInstallerChecker(result)
+	result.ReplQueue = NewReplicationQueueChecker(result)
 	result.StatusStopped = NewStatusStoppedChecker()
 	result.AwaitStopped = NewAwaitChecker(result, "stopped")
 	result.Unreachable = NewReachableChecker(result, false)
@@ -144,6 +146,9 @@ func (im *InstanceManager) AwaitStarted(instances []Instance) error {
 			im.CheckOpts.Installer,
 			im.CheckOpts.LoginPage,
 		}
+		if im.CheckOpts.ReplQueue.Enabled {
+			checkers = append(checkers, im.CheckOpts.ReplQueue)
+		}
 	}
 	return im.CheckUntilDone(instances, im.CheckOpts, checkers)
 }
@@ -17,6 +17,7 @@ import (
 
 const (
 	UnpackDir   = common.VarDir + "/instance"
+	RemoteDir   = common.VarDir + "/remote"
 	BackupDir   = common.VarDir + "/backup"
 	OverrideDir = common.DefaultDir + "/" + common.VarDirName + "/instance"
 
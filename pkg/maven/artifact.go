@@ -0,0 +1,79 @@
+package maven
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ExtensionJar   = "jar"
+	ExtensionZip   = "zip"
+	SnapshotSuffix = "-SNAPSHOT"
+)
+
+// Artifact is identified by Maven coordinates in format 'groupId:artifactId[:extension[:classifier]]:version'
+type Artifact struct {
+	GroupID    string `json:"group_id" yaml:"group_id"`
+	ArtifactID string `json:"artifact_id" yaml:"artifact_id"`
+	Version    string `json:"version" yaml:"version"`
+	Extension  string `json:"extension" yaml:"extension"`
+	Classifier string `json:"classifier,omitempty" yaml:"classifier,omitempty"`
+
+	// SnapshotVersion is a timestamped version of snapshot deployed to remote repository (e.g. '1.0.0-20230314.093000-3')
+	SnapshotVersion string `json:"snapshot_version,omitempty" yaml:"snapshot_version,omitempty"`
+}
+
+// ParseArtifact reads coordinates; extension is used when not specified in coordinates (e.g. 'jar' for bundles, 'zip' for packages)
+func ParseArtifact(coordinates string, extension string) (*Artifact, error) {
+	parts := strings.Split(coordinates, ":")
+	for _, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			return nil, fmt.Errorf("artifact coordinates '%s' have empty parts", coordinates)
+		}
+	}
+	result := Artifact{Extension: extension}
+	switch len(parts) {
+	case 3:
+		result.GroupID, result.ArtifactID, result.Version = parts[0], parts[1], parts[2]
+	case 4:
+		result.GroupID, result.ArtifactID, result.Extension, result.Version = parts[0], parts[1], parts[2], parts[3]
+	case 5:
+		result.GroupID, result.ArtifactID, result.Extension, result.Classifier, result.Version = parts[0], parts[1], parts[2], parts[3], parts[4]
+	default:
+		return nil, fmt.Errorf("artifact coordinates '%s' have invalid format (expected 'groupId:artifactId[:extension[:classifier]]:version')", coordinates)
+	}
+	return &result, nil
+}
+
+func (a Artifact) IsSnapshot() bool {
+	return strings.HasSuffix(a.Version, SnapshotSuffix)
+}
+
+func (a Artifact) FileName() string {
+	version := a.Version
+	if a.SnapshotVersion != "" {
+		version = a.SnapshotVersion
+	}
+	if a.Classifier != "" {
+		return fmt.Sprintf("%s-%s-%s.%s", a.ArtifactID, version, a.Classifier, a.Extension)
+	}
+	return fmt.Sprintf("%s-%s.%s", a.ArtifactID, version, a.Extension)
+}
+
+// Dir is a location of artifact version dir relative to repository root
+func (a Artifact) Dir() string {
+	return fmt.Sprintf("%s/%s/%s", strings.ReplaceAll(a.GroupID, ".", "/"), a.ArtifactID, a.Version)
+}
+
+// Path is a location of artifact file relative to repository root
+func (a Artifact) Path() string {
+	return fmt.Sprintf("%s/%s", a.Dir(), a.FileName())
+}
+
+func (a Artifact) String() string {
+	parts := []string{a.GroupID, a.ArtifactID, a.Extension}
+	if a.Classifier != "" {
+		parts = append(parts, a.Classifier)
+	}
+	return strings.Join(append(parts, a.Version), ":")
+}
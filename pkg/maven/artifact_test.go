@@ -0,0 +1,26 @@
+package maven_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/maven"
+	"testing"
+)
+
+func TestParseArtifact(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	artifact, err := maven.ParseArtifact("org.apache.sling:org.apache.sling.commons.log:5.1.0", maven.ExtensionJar)
+	a.NoError(err)
+	a.Equal("org/apache/sling/org.apache.sling.commons.log/5.1.0/org.apache.sling.commons.log-5.1.0.jar", artifact.Path())
+
+	artifact, err = maven.ParseArtifact("com.adobe.acs:acs-aem-commons-all:zip:min:5.3.4", maven.ExtensionJar)
+	a.NoError(err)
+	a.Equal("com/adobe/acs/acs-aem-commons-all/5.3.4/acs-aem-commons-all-5.3.4-min.zip", artifact.Path())
+	a.Equal("com.adobe.acs:acs-aem-commons-all:zip:min:5.3.4", artifact.String())
+
+	_, err = maven.ParseArtifact("org.apache.sling:org.apache.sling.commons.log", maven.ExtensionJar)
+	a.Error(err)
+	_, err = maven.ParseArtifact("org.apache.sling::5.1.0", maven.ExtensionJar)
+	a.Error(err)
+}
@@ -0,0 +1,131 @@
+package maven_test
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg"
+	"github.com/wttech/aemc/pkg/maven"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveLocal(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	dir := t.TempDir()
+	file := writeArtifact(t, dir, "com/acme/my-app/1.0.0/my-app-1.0.0.zip", "content", "")
+	writeArtifact(t, dir, "com/acme/my-lib/1.0.0/my-lib-1.0.0.jar", "content", "0000000000000000000000000000000000000000")
+
+	opts := mavenOpts(t, "file://"+dir)
+	resolved, err := opts.Resolve("com.acme:my-app:1.0.0", maven.ExtensionZip)
+	a.NoError(err)
+	a.Equal(file, resolved)
+
+	_, err = opts.Resolve("com.acme:my-lib:1.0.0", maven.ExtensionJar)
+	a.ErrorContains(err, "checksum")
+
+	_, err = opts.Resolve("com.acme:my-app:2.0.0", maven.ExtensionZip)
+	a.ErrorContains(err, "cannot find artifact")
+}
+
+func TestResolveRemote(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	files := map[string]string{
+		"/maven2/com/acme/my-app/1.0.0/my-app-1.0.0.zip":                            "release",
+		"/maven2/com/acme/my-app/1.1.0-SNAPSHOT/maven-metadata.xml":                 snapshotMetadata,
+		"/maven2/com/acme/my-app/1.1.0-SNAPSHOT/my-app-1.1.0-20230314.093000-3.zip": "snapshot",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "deployer" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		path := strings.TrimSuffix(r.URL.Path, maven.ChecksumExt)
+		content, ok := files[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if path != r.URL.Path {
+			content = sha1Hex(content)
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	opts := mavenOpts(t, strings.Replace(server.URL, "http://", "http://deployer:secret@", 1)+"/maven2")
+
+	file, err := opts.Resolve("com.acme:my-app:1.0.0", maven.ExtensionZip)
+	a.NoError(err)
+	a.FileExists(file)
+
+	file, err = opts.Resolve("com.acme:my-app:1.1.0-SNAPSHOT", maven.ExtensionZip)
+	a.NoError(err)
+	a.Equal("my-app-1.1.0-20230314.093000-3.zip", filepath.Base(file))
+	content, err := os.ReadFile(file)
+	a.NoError(err)
+	a.Equal("snapshot", string(content))
+
+	_, err = opts.Resolve("com.acme:my-app:2.0.0", maven.ExtensionZip)
+	a.Error(err)
+	a.NotContains(err.Error(), "secret")
+}
+
+const snapshotMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>com.acme</groupId>
+  <artifactId>my-app</artifactId>
+  <version>1.1.0-SNAPSHOT</version>
+  <versioning>
+    <snapshot>
+      <timestamp>20230314.093000</timestamp>
+      <buildNumber>3</buildNumber>
+    </snapshot>
+    <snapshotVersions>
+      <snapshotVersion>
+        <extension>pom</extension>
+        <value>1.1.0-20230314.093000-3</value>
+      </snapshotVersion>
+      <snapshotVersion>
+        <extension>zip</extension>
+        <value>1.1.0-20230314.093000-3</value>
+      </snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>`
+
+func mavenOpts(t *testing.T, repository string) *maven.Opts {
+	aem := pkg.DefaultAEM()
+	aem.BaseOpts().ToolDir = t.TempDir()
+	opts := aem.MavenOpts()
+	opts.Repositories = []string{repository}
+	return opts
+}
+
+func writeArtifact(t *testing.T, dir string, path string, content string, checksum string) string {
+	file := filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if checksum != "" {
+		if err := os.WriteFile(file+maven.ChecksumExt, []byte(checksum), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return file
+}
+
+func sha1Hex(content string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(content)))
+}
@@ -0,0 +1,56 @@
+package maven
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/samber/lo"
+	"strings"
+)
+
+const (
+	MetadataFile = "maven-metadata.xml"
+)
+
+// Metadata describes snapshot versions deployed to remote repository (file 'maven-metadata.xml' located in artifact version dir)
+type Metadata struct {
+	Versioning MetadataVersioning `xml:"versioning"`
+}
+
+type MetadataVersioning struct {
+	Snapshot         MetadataSnapshot          `xml:"snapshot"`
+	SnapshotVersions []MetadataSnapshotVersion `xml:"snapshotVersions>snapshotVersion"`
+}
+
+type MetadataSnapshot struct {
+	Timestamp   string `xml:"timestamp"`
+	BuildNumber int    `xml:"buildNumber"`
+}
+
+type MetadataSnapshotVersion struct {
+	Classifier string `xml:"classifier"`
+	Extension  string `xml:"extension"`
+	Value      string `xml:"value"`
+}
+
+func ParseMetadata(data []byte) (*Metadata, error) {
+	var result Metadata
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("cannot parse Maven metadata: %w", err)
+	}
+	return &result, nil
+}
+
+// SnapshotVersion finds timestamped version of snapshot artifact (e.g. '1.0.0-20230314.093000-3' for '1.0.0-SNAPSHOT')
+func (m Metadata) SnapshotVersion(artifact Artifact) (string, error) {
+	snapshotVersion, ok := lo.Find(m.Versioning.SnapshotVersions, func(v MetadataSnapshotVersion) bool {
+		return v.Extension == artifact.Extension && v.Classifier == artifact.Classifier
+	})
+	if ok {
+		return snapshotVersion.Value, nil
+	}
+	snapshot := m.Versioning.Snapshot
+	if snapshot.Timestamp != "" && snapshot.BuildNumber > 0 {
+		return fmt.Sprintf("%s-%s-%d", strings.TrimSuffix(artifact.Version, SnapshotSuffix), snapshot.Timestamp, snapshot.BuildNumber), nil
+	}
+	return "", fmt.Errorf("cannot find snapshot version of artifact '%s' in Maven metadata", artifact)
+}
@@ -0,0 +1,178 @@
+package osgi
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"regexp"
+	"strings"
+)
+
+// BundleDetails is a response of Felix console for single bundle (e.g. '/system/console/bundles/123.json')
+type BundleDetails struct {
+	Status string              `json:"status"`
+	List   []BundleDetailsItem `json:"data"`
+}
+
+type BundleDetailsItem struct {
+	ID           int                 `json:"id"`
+	SymbolicName string              `json:"symbolicName"`
+	Version      string              `json:"version"`
+	State        string              `json:"state"`
+	StateRaw     int                 `json:"stateRaw"`
+	Fragment     bool                `json:"fragment"`
+	Props        []BundleDetailsProp `json:"props"`
+}
+
+type BundleDetailsProp struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+const (
+	BundlePropImportedPackages = "Imported Packages"
+	BundlePropExportedPackages = "Exported Packages"
+)
+
+// PropValues returns property values as plain text (console renders some of them as HTML)
+func (b BundleDetailsItem) PropValues(key string) []string {
+	prop, ok := lo.Find(b.Props, func(p BundleDetailsProp) bool { return p.Key == key })
+	if !ok {
+		return []string{}
+	}
+	var values []string
+	switch typed := prop.Value.(type) {
+	case []any:
+		values = lo.Map(typed, func(v any, _ int) string { return fmt.Sprintf("%v", v) })
+	default:
+		values = []string{fmt.Sprintf("%v", typed)}
+	}
+	return lo.FilterMap(values, func(v string, _ int) (string, bool) {
+		text := strings.TrimSpace(bundleHTMLTag.ReplaceAllString(v, ""))
+		return text, text != "" && text != "None" && text != "---"
+	})
+}
+
+var bundleHTMLTag = regexp.MustCompile("<[^>]*>")
+
+// PackageImport is an entry of 'Import-Package' as reported by console (e.g. 'org.acme,version=[1.0,2) -- Cannot be resolved')
+type PackageImport struct {
+	Name     string `json:"name" yaml:"name"`
+	Version  string `json:"version" yaml:"version"`
+	Resolved bool   `json:"resolved" yaml:"resolved"`
+	Optional bool   `json:"optional" yaml:"optional"`
+}
+
+// PackageExport is an entry of 'Export-Package' as reported by console (e.g. 'org.acme,version=1.2.0')
+type PackageExport struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+}
+
+func (b BundleDetailsItem) ImportedPackages() []PackageImport {
+	return lo.Map(b.PropValues(BundlePropImportedPackages), func(value string, _ int) PackageImport {
+		name, version := parsePackageEntry(value)
+		return PackageImport{
+			Name:     name,
+			Version:  version,
+			Resolved: !strings.Contains(value, "Cannot be resolved"),
+			Optional: strings.Contains(value, "optional") || strings.Contains(value, "not required"),
+		}
+	})
+}
+
+func (b BundleDetailsItem) MissingImports() []PackageImport {
+	return lo.Filter(b.ImportedPackages(), func(i PackageImport, _ int) bool { return !i.Resolved && !i.Optional })
+}
+
+func (b BundleDetailsItem) ExportedPackages() []PackageExport {
+	return lo.Map(b.PropValues(BundlePropExportedPackages), func(value string, _ int) PackageExport {
+		name, version := parsePackageEntry(value)
+		return PackageExport{Name: name, Version: version}
+	})
+}
+
+func parsePackageEntry(value string) (string, string) {
+	value = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(value, "ERROR:"), "!!"))
+	name, rest, found := strings.Cut(value, ",version=")
+	if !found {
+		return strings.Fields(value + " ")[0], ""
+	}
+	return strings.TrimSpace(name), strings.Fields(rest + " ")[0]
+}
+
+// BundleDiagnosis explains why bundles are not active
+type BundleDiagnosis struct {
+	Bundles []BundleProblem `json:"bundles" yaml:"bundles"`
+}
+
+type BundleProblem struct {
+	ID             int             `json:"id" yaml:"id"`
+	SymbolicName   string          `json:"symbolic_name" yaml:"symbolic_name"`
+	State          string          `json:"state" yaml:"state"`
+	MissingImports []MissingImport `json:"missing_imports" yaml:"missing_imports"`
+}
+
+type MissingImport struct {
+	Package   string            `json:"package" yaml:"package"`
+	Version   string            `json:"version" yaml:"version"`
+	Exporters []PackageExporter `json:"exporters" yaml:"exporters"`
+}
+
+type PackageExporter struct {
+	BundleID     int    `json:"bundle_id" yaml:"bundle_id"`
+	SymbolicName string `json:"symbolic_name" yaml:"symbolic_name"`
+	Version      string `json:"version" yaml:"version"`
+	Matching     bool   `json:"matching" yaml:"matching"`
+}
+
+func (e PackageExporter) String() string {
+	if e.Matching {
+		return fmt.Sprintf("%s (%s)", e.SymbolicName, e.Version)
+	}
+	return fmt.Sprintf("%s (%s, not matching)", e.SymbolicName, e.Version)
+}
+
+func (i MissingImport) String() string {
+	exporters := "not exported"
+	if len(i.Exporters) > 0 {
+		exporters = "exported by " + strings.Join(lo.Map(i.Exporters, func(e PackageExporter, _ int) string { return e.String() }), ", ")
+	}
+	return fmt.Sprintf("%s: %s", strings.TrimSpace(i.Package+" "+i.Version), exporters)
+}
+
+func (d BundleDiagnosis) Empty() bool {
+	return len(d.Bundles) == 0
+}
+
+// Summary is a one-line explanation suitable for check messages
+func (d BundleDiagnosis) Summary() string {
+	return strings.Join(lo.Map(d.Bundles, func(b BundleProblem, _ int) string {
+		if len(b.MissingImports) == 0 {
+			return fmt.Sprintf("'%s' %s", b.SymbolicName, strings.ToLower(b.State))
+		}
+		return fmt.Sprintf("'%s' missing %s", b.SymbolicName, strings.Join(lo.Map(b.MissingImports, func(i MissingImport, _ int) string { return i.String() }), "; "))
+	}), ", ")
+}
+
+func (d BundleDiagnosis) MarshalText() string {
+	bs := bytes.NewBufferString("")
+	var rows []map[string]any
+	for _, b := range d.Bundles {
+		if len(b.MissingImports) == 0 {
+			rows = append(rows, map[string]any{"symbolic name": b.SymbolicName, "state": b.State, "package": "", "exporters": ""})
+		}
+		for _, i := range b.MissingImports {
+			exporters := lo.Map(i.Exporters, func(e PackageExporter, _ int) string { return e.String() })
+			rows = append(rows, map[string]any{
+				"symbolic name": b.SymbolicName,
+				"state":         b.State,
+				"package":       strings.TrimSpace(i.Package + " " + i.Version),
+				"exporters":     strings.Join(exporters, ", "),
+			})
+		}
+	}
+	bs.WriteString(fmtx.TblRows("problems", false, []string{"symbolic name", "state", "package", "exporters"}, rows))
+	return bs.String()
+}
@@ -0,0 +1,53 @@
+package osgi_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/osgi"
+	"testing"
+)
+
+func TestBundleDetailsMissingImports(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var details osgi.BundleDetails
+	a.NoError(json.Unmarshal([]byte(`{
+		"status": "Bundle information: 1 bundle in total.",
+		"data": [{
+			"id": 512,
+			"symbolicName": "com.acme.core",
+			"state": "Installed",
+			"stateRaw": 2,
+			"props": [
+				{"key": "Imported Packages", "value": [
+					"org.apache.sling.api,version=[2.3,3) from <a href='/system/console/bundles/88'>org.apache.sling.api (88)</a>",
+					"<span class='ui-state-error-text'><b>ERROR: com.google.gson,version=[2.9,3) -- Cannot be resolved</b></span>",
+					"<span class='ui-state-error-text'><b>ERROR: org.slf4j.ext,version=[1.7,2) -- Cannot be resolved but is optional</b></span>"
+				]},
+				{"key": "Exported Packages", "value": ["com.acme.core.api,version=1.0.0"]}
+			]
+		}]
+	}`), &details))
+
+	bundle := details.List[0]
+	missing := bundle.MissingImports()
+	a.Len(missing, 1)
+	a.Equal("com.google.gson", missing[0].Name)
+	a.Equal("[2.9,3)", missing[0].Version)
+	a.Equal([]osgi.PackageExport{{Name: "com.acme.core.api", Version: "1.0.0"}}, bundle.ExportedPackages())
+}
+
+func TestBundleDiagnosisSummary(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	diagnosis := osgi.BundleDiagnosis{Bundles: []osgi.BundleProblem{
+		{SymbolicName: "com.acme.core", State: "Installed", MissingImports: []osgi.MissingImport{
+			{Package: "com.google.gson", Version: "[2.9,3)", Exporters: []osgi.PackageExporter{{SymbolicName: "com.google.gson", Version: "2.8.5"}}},
+			{Package: "org.acme.missing", Version: "[1.0,2)"},
+		}},
+		{SymbolicName: "com.acme.lazy", State: "Resolved"},
+	}}
+	a.Equal("'com.acme.core' missing com.google.gson [2.9,3): exported by com.google.gson (2.8.5, not matching); org.acme.missing [1.0,2): not exported, 'com.acme.lazy' resolved", diagnosis.Summary())
+}
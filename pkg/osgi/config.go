@@ -2,14 +2,32 @@ package osgi
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/samber/lo"
 	"github.com/wttech/aemc/pkg/common/fmtx"
+	"github.com/wttech/aemc/pkg/common/mapsx"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 type ConfigPIDs struct {
 	PIDs []ConfigPID
 }
 
+// Prunable returns PIDs of existing configs having one of prefixes but not being kept (PIDs must be the actual ones, e.g. generated 'factoryPid.uuid')
+func (p ConfigPIDs) Prunable(kept []string, prefixes []string) []string {
+	var result []string
+	for _, pid := range p.PIDs {
+		if !pid.HasConfig || lo.Contains(kept, pid.ID) || !lo.SomeBy(prefixes, func(prefix string) bool { return strings.HasPrefix(pid.ID, prefix) }) {
+			continue
+		}
+		result = append(result, pid.ID)
+	}
+	return result
+}
+
 type ConfigPID struct {
 	ID        string `json:"id"`
 	Name      string `json:"name"`
@@ -23,6 +41,7 @@ type ConfigListItem struct {
 	Title           string                    `json:"title"`
 	Description     string                    `json:"description"`
 	Properties      map[string]map[string]any `json:"properties"`
+	FactoryPID      string                    `json:"factoryPid"`
 	BundleLocation  string                    `json:"bundle_location"`
 	ServiceLocation string                    `json:"service_location"`
 }
@@ -43,6 +62,38 @@ func (c ConfigListItem) PropertyValues() map[string]any {
 	return result
 }
 
+// ConfigPropsEqual checks if updated props would not change current ones; values are compared as text
+// as types read from config files (e.g. int64, []any) differ from those rendered by the console (e.g. float64, strings)
+func ConfigPropsEqual(current map[string]any, updated map[string]any) bool {
+	return mapsx.Equal(configPropsAsText(current), configPropsAsText(updated))
+}
+
+func configPropsAsText(props map[string]any) map[string]any {
+	return lo.MapValues(props, func(value any, _ string) any { return configValueAsText(value) })
+}
+
+func configValueAsText(value any) any {
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return typed
+	case float32:
+		return strconv.FormatFloat(float64(typed), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		result := make([]any, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			result[i] = configValueAsText(rv.Index(i).Interface())
+		}
+		return result
+	}
+	return fmt.Sprintf("%v", value)
+}
+
 type ConfigList struct {
 	List []ConfigListItem
 }
@@ -54,3 +105,23 @@ func (cl ConfigList) MarshalText() string {
 	})))
 	return bs.String()
 }
+
+// ConfigFactoryPID builds PID of named factory config (e.g. 'org.apache.sling.commons.log.LogManager.factory.config~my-app')
+func ConfigFactoryPID(factoryPID string, name string) string {
+	return factoryPID + ConfigFactoryDelimiter + name
+}
+
+// ConfigFactoryName extracts name of factory config from its PID; supports both named ('factoryPid~name') and generated ('factoryPid.uuid') PIDs
+func ConfigFactoryName(factoryPID string, pid string) (string, bool) {
+	namedPrefix := factoryPID + ConfigFactoryDelimiter
+	if strings.HasPrefix(pid, namedPrefix) && len(pid) > len(namedPrefix) {
+		return strings.TrimPrefix(pid, namedPrefix), true
+	}
+	generatedName := strings.TrimPrefix(pid, factoryPID+".")
+	if generatedName != pid && configFactoryUUID.MatchString(generatedName) {
+		return generatedName, true
+	}
+	return "", false
+}
+
+var configFactoryUUID = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
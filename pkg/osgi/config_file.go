@@ -0,0 +1,483 @@
+package osgi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ConfigFileExt          = ".cfg.json"
+	ConfigFileExtLegacy    = ".config"
+	ConfigFactoryDelimiter = "~"
+	ConfigDirName          = "config"
+)
+
+// ConfigFile is a Sling OSGi configuration file (e.g. 'org.apache.sling.commons.log.LogManager.factory.config~my-app.cfg.json')
+type ConfigFile struct {
+	Path  string         `json:"path" yaml:"path"`
+	PID   string         `json:"pid" yaml:"pid"`
+	Props map[string]any `json:"props" yaml:"props"`
+}
+
+func IsConfigFile(path string) bool {
+	return strings.HasSuffix(path, ConfigFileExt) || strings.HasSuffix(path, ConfigFileExtLegacy)
+}
+
+// ConfigFilePID determines PID from file name; for factory configs PID is in format 'factoryPid~name'
+func ConfigFilePID(path string) string {
+	name := filepath.Base(path)
+	if strings.HasSuffix(name, ConfigFileExt) {
+		return strings.TrimSuffix(name, ConfigFileExt)
+	}
+	return strings.TrimSuffix(name, ConfigFileExtLegacy)
+}
+
+func ReadConfigFile(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read OSGi config file '%s': %w", path, err)
+	}
+	var props map[string]any
+	if strings.HasSuffix(path, ConfigFileExt) {
+		props, err = ParseConfigJSON(data)
+	} else {
+		props, err = ParseConfigLegacy(string(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse OSGi config file '%s': %w", path, err)
+	}
+	return &ConfigFile{Path: path, PID: ConfigFilePID(path), Props: props}, nil
+}
+
+// ParseConfigJSON reads '.cfg.json' format; line comments are allowed and type hints in keys are applied (e.g. 'port:Integer')
+func ParseConfigJSON(data []byte) (map[string]any, error) {
+	lines := lo.Filter(strings.Split(string(data), "\n"), func(line string, _ int) bool {
+		return !strings.HasPrefix(strings.TrimSpace(line), "//")
+	})
+	decoder := json.NewDecoder(strings.NewReader(strings.Join(lines, "\n")))
+	decoder.UseNumber()
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	result := map[string]any{}
+	for key, value := range raw {
+		if strings.HasPrefix(key, ":configurator:") {
+			continue
+		}
+		name, hint, _ := strings.Cut(key, ":")
+		result[name] = configJSONValue(value, hint)
+	}
+	return result, nil
+}
+
+func configJSONValue(value any, hint string) any {
+	switch typed := value.(type) {
+	case []any:
+		elementHint := strings.TrimSuffix(hint, "[]")
+		return lo.Map(typed, func(v any, _ int) any { return configJSONValue(v, elementHint) })
+	case json.Number:
+		switch hint {
+		case "Integer", "int", "Long", "long", "Short", "short", "Byte", "byte":
+			number, _ := typed.Int64()
+			return number
+		case "Float", "float", "Double", "double":
+			number, _ := typed.Float64()
+			return number
+		case "String":
+			return typed.String()
+		}
+		if number, err := typed.Int64(); err == nil {
+			return number
+		}
+		number, _ := typed.Float64()
+		return number
+	case string:
+		return configScalar(typed, configTypeCode(hint))
+	default:
+		return value
+	}
+}
+
+func configTypeCode(hint string) string {
+	switch hint {
+	case "Integer", "int":
+		return "I"
+	case "Long", "long":
+		return "L"
+	case "Float", "float":
+		return "F"
+	case "Double", "double":
+		return "D"
+	case "Boolean", "boolean":
+		return "B"
+	default:
+		return ""
+	}
+}
+
+// ParseConfigLegacy reads Felix '.config' format (e.g. 'port=I"8080"', 'names=["a","b"]')
+func ParseConfigLegacy(text string) (map[string]any, error) {
+	result := map[string]any{}
+	for _, line := range joinConfigLines(text) {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line '%s'", trimmed)
+		}
+		parsed, err := parseConfigLegacyValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of property '%s': %w", strings.TrimSpace(key), err)
+		}
+		result[strings.TrimSpace(key)] = parsed
+	}
+	return result, nil
+}
+
+// joinConfigLines merges multi-line values (arrays split with trailing backslashes or unclosed brackets)
+func joinConfigLines(text string) []string {
+	var result []string
+	current := ""
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasSuffix(trimmed, "\\") && !strings.HasSuffix(trimmed, "\\\\") {
+			current += strings.TrimSuffix(trimmed, "\\")
+			continue
+		}
+		current += trimmed
+		if strings.Count(current, "[")+strings.Count(current, "(") > strings.Count(current, "]")+strings.Count(current, ")") {
+			continue
+		}
+		result = append(result, current)
+		current = ""
+	}
+	if current != "" {
+		result = append(result, current)
+	}
+	return result
+}
+
+func parseConfigLegacyValue(value string) (any, error) {
+	typeCode := ""
+	if len(value) > 0 && value[0] != '"' && value[0] != '[' && value[0] != '(' {
+		typeCode = value[:1]
+		value = value[1:]
+	}
+	if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "(") {
+		inner := strings.TrimSpace(value[1 : len(value)-1])
+		if inner == "" {
+			return []any{}, nil
+		}
+		var items []any
+		for _, item := range splitConfigArray(inner) {
+			text, err := unquoteConfigValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, configScalar(text, typeCode))
+		}
+		return items, nil
+	}
+	text, err := unquoteConfigValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return configScalar(text, typeCode), nil
+}
+
+func splitConfigArray(value string) []string {
+	var result []string
+	current := strings.Builder{}
+	quoted := false
+	escaped := false
+	for _, char := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == '"':
+			quoted = !quoted
+		case char == ',' && !quoted:
+			result = append(result, strings.TrimSpace(current.String()))
+			current.Reset()
+			continue
+		}
+		current.WriteRune(char)
+	}
+	if strings.TrimSpace(current.String()) != "" {
+		result = append(result, strings.TrimSpace(current.String()))
+	}
+	return result
+}
+
+func unquoteConfigValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 || !strings.HasPrefix(value, "\"") || !strings.HasSuffix(value, "\"") {
+		return "", fmt.Errorf("value '%s' is not quoted", value)
+	}
+	replacer := strings.NewReplacer("\\\"", "\"", "\\\\", "\\", "\\=", "=", "\\ ", " ")
+	return replacer.Replace(value[1 : len(value)-1]), nil
+}
+
+func configScalar(value string, typeCode string) any {
+	switch typeCode {
+	case "I", "i", "L", "l", "S", "s", "X", "x":
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return number
+		}
+	case "F", "f", "D", "d":
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case "B", "b":
+		if flag, err := strconv.ParseBool(value); err == nil {
+			return flag
+		}
+	}
+	return value
+}
+
+// ConfigDirs selects dirs applicable for run modes ordered from the least to the most specific (e.g. 'config', 'config.author', 'config.author.dev');
+// when dir has no such subdirs, it is returned as is
+func ConfigDirs(dir string, runModes []string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read OSGi config dir '%s': %w", dir, err)
+	}
+	type runModeDir struct {
+		name     string
+		runModes []string
+	}
+	var dirs []runModeDir
+	hasRunModeDirs := false
+	for _, entry := range entries {
+		if !entry.IsDir() || (entry.Name() != ConfigDirName && !strings.HasPrefix(entry.Name(), ConfigDirName+".")) {
+			continue
+		}
+		hasRunModeDirs = true
+		dirRunModes := lo.Filter(strings.Split(entry.Name(), ".")[1:], func(rm string, _ int) bool { return rm != "" })
+		if lo.Every(runModes, dirRunModes) {
+			dirs = append(dirs, runModeDir{name: entry.Name(), runModes: dirRunModes})
+		}
+	}
+	if !hasRunModeDirs {
+		return []string{dir}, nil
+	}
+	sort.SliceStable(dirs, func(i, j int) bool {
+		if len(dirs[i].runModes) != len(dirs[j].runModes) {
+			return len(dirs[i].runModes) < len(dirs[j].runModes)
+		}
+		return dirs[i].name < dirs[j].name
+	})
+	return lo.Map(dirs, func(d runModeDir, _ int) string { return filepath.Join(dir, d.name) }), nil
+}
+
+// ReadConfigDir reads config files applicable for run modes; files from more specific dirs override the same PIDs
+func ReadConfigDir(dir string, runModes []string) ([]ConfigFile, error) {
+	dirs, err := ConfigDirs(dir, runModes)
+	if err != nil {
+		return nil, err
+	}
+	byPID := map[string]ConfigFile{}
+	for _, configDir := range dirs {
+		entries, err := os.ReadDir(configDir)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read OSGi config dir '%s': %w", configDir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !IsConfigFile(entry.Name()) {
+				continue
+			}
+			file, err := ReadConfigFile(filepath.Join(configDir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			byPID[file.PID] = *file
+		}
+	}
+	result := lo.Values(byPID)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].PID < result[j].PID })
+	return result, nil
+}
+
+const (
+	ConfigApplyChanged   = "changed"
+	ConfigApplyUnchanged = "unchanged"
+	ConfigApplyDeleted   = "deleted"
+)
+
+// ConfigApplyReport summarizes applying configs from dir to instance
+type ConfigApplyReport struct {
+	Dir     string              `json:"dir" yaml:"dir"`
+	Results []ConfigApplyResult `json:"results" yaml:"results"`
+}
+
+type ConfigApplyResult struct {
+	PID    string `json:"pid" yaml:"pid"`
+	File   string `json:"file,omitempty" yaml:"file,omitempty"`
+	Action string `json:"action" yaml:"action"`
+}
+
+func (r ConfigApplyReport) Changed() bool {
+	return lo.SomeBy(r.Results, func(result ConfigApplyResult) bool { return result.Action != ConfigApplyUnchanged })
+}
+
+func (r ConfigApplyReport) MarshalText() string {
+	bs := bytes.NewBufferString("")
+	bs.WriteString(fmtx.TblRows("configs", true, []string{"pid", "action", "file"}, lo.Map(r.Results, func(result ConfigApplyResult, _ int) map[string]any {
+		return map[string]any{"pid": result.PID, "action": result.Action, "file": result.File}
+	})))
+	return bs.String()
+}
+
+const (
+	ConfigExportFormatJSON = "cfg.json"
+)
+
+// ConfigExportPropsIgnored are set by framework so they should not be stored in config files
+var ConfigExportPropsIgnored = []string{"service.pid", "service.factoryPid", "service.bundleLocation", "felix.fileinstall.filename"}
+
+// Metatype attribute types as reported by Felix console (see 'org.osgi.service.metatype.AttributeDefinition')
+const (
+	configTypeString    = 1
+	configTypeLong      = 2
+	configTypeInteger   = 3
+	configTypeShort     = 4
+	configTypeCharacter = 5
+	configTypeByte      = 6
+	configTypeDouble    = 7
+	configTypeFloat     = 8
+	configTypeBigInt    = 9
+	configTypeBigDec    = 10
+	configTypeBoolean   = 11
+	configTypePassword  = 12
+)
+
+// FilePID determines PID used in config file name; factory configs with generated PIDs are named by their UUIDs
+func (c ConfigListItem) FilePID() string {
+	if c.FactoryPID == "" || strings.Contains(c.PID, ConfigFactoryDelimiter) {
+		return c.PID
+	}
+	name, ok := ConfigFactoryName(c.FactoryPID, c.PID)
+	if !ok {
+		name = strings.TrimPrefix(strings.TrimPrefix(c.PID, c.FactoryPID), ".")
+	}
+	return ConfigFactoryPID(c.FactoryPID, name)
+}
+
+// FileProps returns properties with values converted to their types; keys have type hints when type cannot be inferred from JSON value (e.g. 'port:Integer')
+func (c ConfigListItem) FileProps() map[string]any {
+	result := map[string]any{}
+	for name, def := range c.Properties {
+		if lo.Contains(ConfigExportPropsIgnored, name) {
+			continue
+		}
+		typeCode := configTypeOf(def["type"])
+		if values, ok := def["values"]; ok {
+			items, _ := values.([]any)
+			result[name+configTypeHint(typeCode, true)] = lo.Map(items, func(v any, _ int) any { return configTypedValue(v, typeCode) })
+		} else if value, ok := def["value"]; ok {
+			result[name+configTypeHint(typeCode, false)] = configTypedValue(value, typeCode)
+		}
+	}
+	return result
+}
+
+func (c ConfigListItem) MarshalConfigJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(c.FileProps(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// configTypeOf reads type code; for options-based properties type is an object without code so string is assumed
+func configTypeOf(value any) int {
+	if number, ok := value.(float64); ok {
+		return int(number)
+	}
+	return configTypeString
+}
+
+func configTypeHint(typeCode int, array bool) string {
+	var hint string
+	switch typeCode {
+	case configTypeInteger:
+		hint = "Integer"
+	case configTypeShort:
+		hint = "Short"
+	case configTypeByte:
+		hint = "Byte"
+	case configTypeFloat:
+		hint = "Float"
+	case configTypeDouble:
+		hint = "Double"
+	case configTypeCharacter:
+		hint = "Character"
+	default:
+		return ""
+	}
+	if array {
+		hint += "[]"
+	}
+	return ":" + hint
+}
+
+func configTypedValue(value any, typeCode int) any {
+	text := fmt.Sprintf("%v", value)
+	switch typeCode {
+	case configTypeLong, configTypeInteger, configTypeShort, configTypeByte, configTypeBigInt:
+		if number, ok := value.(float64); ok {
+			return int64(number)
+		}
+		if number, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return number
+		}
+	case configTypeDouble, configTypeFloat, configTypeBigDec:
+		if number, ok := value.(float64); ok {
+			return number
+		}
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return number
+		}
+	case configTypeBoolean:
+		if flag, ok := value.(bool); ok {
+			return flag
+		}
+		if flag, err := strconv.ParseBool(text); err == nil {
+			return flag
+		}
+	}
+	if _, ok := value.(string); ok {
+		return value
+	}
+	return text
+}
+
+// WriteConfigFile saves config in dir using Sling '.cfg.json' format
+func WriteConfigFile(dir string, config ConfigListItem) (string, error) {
+	path := filepath.Join(dir, config.FilePID()+ConfigFileExt)
+	data, err := config.MarshalConfigJSON()
+	if err != nil {
+		return "", fmt.Errorf("cannot serialize OSGi config '%s': %w", config.PID, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("cannot create OSGi config dir '%s': %w", dir, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("cannot write OSGi config file '%s': %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,124 @@
+package osgi_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/osgi"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseConfigJSON(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	props, err := osgi.ParseConfigJSON([]byte(`{
+		// comment
+		"enabled": true,
+		"port:Integer": "8080",
+		"ratio": 0.5,
+		"names": ["a", "b"],
+		"ids:Long[]": [1, 2]
+	}`))
+	a.NoError(err)
+	a.Equal(map[string]any{
+		"enabled": true,
+		"port":    int64(8080),
+		"ratio":   0.5,
+		"names":   []any{"a", "b"},
+		"ids":     []any{int64(1), int64(2)},
+	}, props)
+}
+
+func TestParseConfigLegacy(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	props, err := osgi.ParseConfigLegacy(`# comment
+enabled=B"true"
+port=I"8080"
+name="my \"app\""
+names=[ \
+  "a", \
+  "b,c", \
+  ]
+`)
+	a.NoError(err)
+	a.Equal(map[string]any{
+		"enabled": true,
+		"port":    int64(8080),
+		"name":    `my "app"`,
+		"names":   []any{"a", "b,c"},
+	}, props)
+}
+
+func TestReadConfigDir(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"config/com.acme.Service.cfg.json":                   `{"mode": "default"}`,
+		"config.author/com.acme.Service.cfg.json":            `{"mode": "author"}`,
+		"config.author.dev/com.acme.Factory~site.cfg.json":   `{"site": "dev"}`,
+		"config.publish/com.acme.Service.cfg.json":           `{"mode": "publish"}`,
+		"config.author.prod/com.acme.Factory~site.cfg.json":  `{"site": "prod"}`,
+		"config.author/org.apache.sling.Legacy.config":       `enabled=B"false"`,
+		"config.author/README.md":                            `not a config`,
+		"config.author.dev/com.acme.Factory~other.cfg.json":  `{"site": "other"}`,
+		"config.author.dev/com.acme.Service.cfg.json.backup": `{}`,
+	}
+	for path, content := range files {
+		a.NoError(os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0755))
+		a.NoError(os.WriteFile(filepath.Join(dir, path), []byte(content), 0644))
+	}
+
+	configs, err := osgi.ReadConfigDir(dir, []string{"author", "dev", "local"})
+	a.NoError(err)
+	a.Len(configs, 4)
+	a.Equal("com.acme.Factory~other", configs[0].PID)
+	a.Equal("com.acme.Factory~site", configs[1].PID)
+	a.Equal("dev", configs[1].Props["site"])
+	a.Equal("com.acme.Service", configs[2].PID)
+	a.Equal("author", configs[2].Props["mode"])
+	a.Equal("org.apache.sling.Legacy", configs[3].PID)
+	a.Equal(false, configs[3].Props["enabled"])
+}
+
+func TestWriteConfigFile(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var config osgi.ConfigListItem
+	a.NoError(json.Unmarshal([]byte(`{
+		"pid": "com.acme.Factory.4f9a3c0e-6d8b-4d4e-9a53-2b1f0c7e8d11",
+		"factoryPid": "com.acme.Factory",
+		"title": "Acme Factory",
+		"properties": {
+			"enabled": {"type": 11, "value": true},
+			"timeout": {"type": 2, "value": 5000},
+			"port": {"type": 3, "value": "8080"},
+			"ratio": {"type": 7, "value": 1},
+			"paths": {"type": 1, "values": ["/content", "/conf"]},
+			"mode": {"type": {"labels": ["Fast"], "values": ["fast"]}, "value": "fast"},
+			"service.pid": {"type": 1, "value": "com.acme.Factory.4f9a3c0e-6d8b-4d4e-9a53-2b1f0c7e8d11"}
+		}
+	}`), &config))
+
+	dir := t.TempDir()
+	path, err := osgi.WriteConfigFile(dir, config)
+	a.NoError(err)
+	a.Equal(filepath.Join(dir, "com.acme.Factory~4f9a3c0e-6d8b-4d4e-9a53-2b1f0c7e8d11.cfg.json"), path)
+
+	file, err := osgi.ReadConfigFile(path)
+	a.NoError(err)
+	a.Equal(map[string]any{
+		"enabled": true,
+		"timeout": int64(5000),
+		"port":    int64(8080),
+		"ratio":   float64(1),
+		"paths":   []any{"/content", "/conf"},
+		"mode":    "fast",
+	}, file.Props)
+}
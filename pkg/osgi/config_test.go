@@ -0,0 +1,27 @@
+package osgi_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/osgi"
+	"testing"
+)
+
+func TestConfigFactoryName(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	factoryPID := "org.apache.sling.commons.log.LogManager.factory.config"
+
+	name, ok := osgi.ConfigFactoryName(factoryPID, osgi.ConfigFactoryPID(factoryPID, "my-app"))
+	a.True(ok)
+	a.Equal("my-app", name)
+
+	name, ok = osgi.ConfigFactoryName(factoryPID, factoryPID+".4f9a3c0e-6d8b-4d4e-9a53-2b1f0c7e8d11")
+	a.True(ok)
+	a.Equal("4f9a3c0e-6d8b-4d4e-9a53-2b1f0c7e8d11", name)
+
+	_, ok = osgi.ConfigFactoryName(factoryPID, factoryPID+".writer")
+	a.False(ok)
+	_, ok = osgi.ConfigFactoryName(factoryPID, factoryPID)
+	a.False(ok)
+}
@@ -1,8 +1,11 @@
 package osgi
 
 import (
+	"bufio"
 	"fmt"
 	"github.com/essentialkaos/go-jar"
+	"io"
+	"strings"
 )
 
 func ReadBundleManifest(localPath string) (*BundleManifest, error) {
@@ -13,12 +16,41 @@ func ReadBundleManifest(localPath string) (*BundleManifest, error) {
 	return &BundleManifest{SymbolicName: manifest[AttributeSymbolicName], Version: manifest[AttributeVersion]}, nil
 }
 
+// ParseBundleManifest reads manifest of bundle not available as a file (e.g. embedded in package)
+func ParseBundleManifest(reader io.Reader) (*BundleManifest, error) {
+	attrs := map[string]string{}
+	scanner := bufio.NewScanner(reader)
+	var name string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 {
+			continue
+		}
+		if strings.HasPrefix(line, " ") {
+			attrs[name] += strings.TrimPrefix(line, " ")
+			continue
+		}
+		attrName, attrValue, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("cannot parse OSGi bundle manifest line '%s'", line)
+		}
+		name = attrName
+		attrs[name] = attrValue
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read OSGi bundle manifest: %w", err)
+	}
+	symbolicName, _, _ := strings.Cut(attrs[AttributeSymbolicName], ";") // skip directives like 'singleton:=true'
+	return &BundleManifest{SymbolicName: symbolicName, Version: attrs[AttributeVersion]}, nil
+}
+
 type BundleManifest struct {
 	SymbolicName string
 	Version      string
 }
 
 const (
+	ManifestPath          = "META-INF/MANIFEST.MF"
 	AttributeSymbolicName = "Bundle-SymbolicName"
 	AttributeVersion      = "Bundle-Version"
 )
@@ -5,6 +5,8 @@ import (
 	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 	"github.com/wttech/aemc/pkg/common/fmtx"
+	"github.com/wttech/aemc/pkg/common/lox"
+	"github.com/wttech/aemc/pkg/common/versionx"
 	"github.com/wttech/aemc/pkg/osgi"
 )
 
@@ -150,7 +152,87 @@ func (bm *OSGiBundleManager) Uninstall(id int) error {
 	return nil
 }
 
+func (bm *OSGiBundleManager) Details(id int) (*osgi.BundleDetailsItem, error) {
+	resp, err := bm.instance.http.Request().Get(fmt.Sprintf("%s/%d.json", BundlesPath, id))
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot request details of bundle '%d': %w", bm.instance.ID(), id, err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("%s > cannot request details of bundle '%d': %s", bm.instance.ID(), id, resp.Status())
+	}
+	var res osgi.BundleDetails
+	if err = fmtx.UnmarshalJSON(resp.RawBody(), &res); err != nil {
+		return nil, fmt.Errorf("%s > cannot parse details of bundle '%d': %w", bm.instance.ID(), id, err)
+	}
+	if len(res.List) == 0 {
+		return nil, fmt.Errorf("%s > cannot find details of bundle '%d'", bm.instance.ID(), id)
+	}
+	return &res.List[0], nil
+}
+
+// Diagnose explains why bundles are installed or resolved but not active; for unsatisfied imports, bundles exporting same packages are looked up
+func (bm *OSGiBundleManager) Diagnose() (*osgi.BundleDiagnosis, error) {
+	bundles, err := bm.List()
+	if err != nil {
+		return nil, err
+	}
+	unstable := lo.Filter(bundles.FindUnstable(), func(b osgi.BundleListItem, _ int) bool {
+		return b.StateRaw == int(osgi.BundleStateRawInstalled) || b.StateRaw == int(osgi.BundleStateRawResolved)
+	})
+	result := &osgi.BundleDiagnosis{}
+	var missingPackages []string
+	for _, bundle := range unstable {
+		details, err := bm.Details(bundle.ID)
+		if err != nil {
+			return nil, err
+		}
+		problem := osgi.BundleProblem{ID: bundle.ID, SymbolicName: bundle.SymbolicName, State: bundle.State}
+		for _, missing := range details.MissingImports() {
+			problem.MissingImports = append(problem.MissingImports, osgi.MissingImport{Package: missing.Name, Version: missing.Version})
+			missingPackages = append(missingPackages, missing.Name)
+		}
+		result.Bundles = append(result.Bundles, problem)
+	}
+	if len(missingPackages) == 0 {
+		return result, nil
+	}
+	exporters, err := bm.findExporters(bundles.List, missingPackages)
+	if err != nil {
+		return nil, err
+	}
+	for bi, problem := range result.Bundles {
+		for ii, missing := range problem.MissingImports {
+			result.Bundles[bi].MissingImports[ii].Exporters = lo.Map(exporters[missing.Package], func(e osgi.PackageExporter, _ int) osgi.PackageExporter {
+				e.Matching = versionx.InRange(e.Version, missing.Version)
+				return e
+			})
+		}
+	}
+	return result, nil
+}
+
+// findExporters reads details of all bundles as console does not offer package index
+func (bm *OSGiBundleManager) findExporters(bundles []osgi.BundleListItem, packages []string) (map[string][]osgi.PackageExporter, error) {
+	detailsList, err := lox.LimitedMap(BundleDetailsConcurrency, bundles, func(bundle osgi.BundleListItem) (*osgi.BundleDetailsItem, error) {
+		return bm.Details(bundle.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := map[string][]osgi.PackageExporter{}
+	for _, details := range detailsList {
+		for _, export := range details.ExportedPackages() {
+			if lo.Contains(packages, export.Name) {
+				result[export.Name] = append(result[export.Name], osgi.PackageExporter{BundleID: details.ID, SymbolicName: details.SymbolicName, Version: export.Version})
+			}
+		}
+	}
+	return result, nil
+}
+
 const (
+	BundleDetailsConcurrency = 8
+
 	BundlesPath     = "/system/console/bundles"
 	BundlesPathJson = BundlesPath + ".json"
 )
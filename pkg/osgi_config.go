@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/wttech/aemc/pkg/common/fmtx"
-	"github.com/wttech/aemc/pkg/common/mapsx"
 	"github.com/wttech/aemc/pkg/osgi"
 	"golang.org/x/exp/maps"
 )
@@ -82,7 +81,7 @@ func (c OSGiConfig) SaveWithChanged(props map[string]any) (bool, error) {
 		return true, nil
 	}
 	propsBefore := maps.Clone(state.Properties)
-	if mapsx.Equal(propsBefore, props) {
+	if osgi.ConfigPropsEqual(propsBefore, props) {
 		return false, nil
 	}
 	err = c.manager.Save(c.pid, props)
@@ -93,7 +92,7 @@ func (c OSGiConfig) SaveWithChanged(props map[string]any) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return !mapsx.Equal(propsBefore, state.Properties), nil
+	return !osgi.ConfigPropsEqual(propsBefore, state.Properties), nil
 }
 
 func (c OSGiConfig) Delete() error {
@@ -0,0 +1,142 @@
+package pkg
+
+import (
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/osgi"
+	"sort"
+)
+
+// OSGiConfigFactory manages instances of factory config identified by names (PIDs 'factoryPid~name' or generated 'factoryPid.uuid')
+type OSGiConfigFactory struct {
+	manager    *OSGiConfigManager
+	factoryPID string
+}
+
+func (cm *OSGiConfigManager) Factory(factoryPID string) OSGiConfigFactory {
+	return OSGiConfigFactory{manager: cm, factoryPID: factoryPID}
+}
+
+func (f OSGiConfigFactory) FactoryPID() string {
+	return f.factoryPID
+}
+
+func (f OSGiConfigFactory) pids() ([]string, error) {
+	pidList, err := f.manager.listPIDs()
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, pid := range pidList.PIDs {
+		if _, ok := osgi.ConfigFactoryName(f.factoryPID, pid.ID); ok || pid.FPID == f.factoryPID {
+			result = append(result, pid.ID)
+		}
+	}
+	sort.Strings(result)
+	return lo.Uniq(result), nil
+}
+
+func (f OSGiConfigFactory) List() (*osgi.ConfigList, error) {
+	pids, err := f.pids()
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot list configs of factory '%s': %w", f.manager.instance.ID(), f.factoryPID, err)
+	}
+	var result []osgi.ConfigListItem
+	for _, pid := range pids {
+		config, err := f.manager.Find(pid)
+		if err != nil {
+			return nil, err
+		}
+		if config != nil {
+			result = append(result, *config)
+		}
+	}
+	return &osgi.ConfigList{List: result}, nil
+}
+
+// Find returns config having given name or generated UUID; nil if it does not exist
+func (f OSGiConfigFactory) Find(name string) (*OSGiConfig, error) {
+	pids, err := f.pids()
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot find config '%s' of factory '%s': %w", f.manager.instance.ID(), name, f.factoryPID, err)
+	}
+	pid, ok := lo.Find(pids, func(pid string) bool {
+		pidName, _ := osgi.ConfigFactoryName(f.factoryPID, pid)
+		return pidName == name
+	})
+	if !ok {
+		return nil, nil
+	}
+	config := f.manager.ByPID(pid)
+	return &config, nil
+}
+
+// ByName returns existing config or the one which would be created when saving
+func (f OSGiConfigFactory) ByName(name string) (OSGiConfig, error) {
+	config, err := f.Find(name)
+	if err != nil {
+		return OSGiConfig{}, err
+	}
+	if config != nil {
+		return *config, nil
+	}
+	return f.manager.ByPID(osgi.ConfigFactoryPID(f.factoryPID, name)), nil
+}
+
+func (f OSGiConfigFactory) Save(name string, props map[string]any) error {
+	config, err := f.Find(name)
+	if err != nil {
+		return err
+	}
+	if config != nil {
+		return config.Save(props)
+	}
+	return f.create(name, props)
+}
+
+func (f OSGiConfigFactory) SaveWithChanged(name string, props map[string]any) (bool, error) {
+	_, changed, err := f.saveWithChanged(name, props)
+	return changed, err
+}
+
+// saveWithChanged also returns PID of the saved config which could be a generated one (e.g. 'factoryPid.uuid')
+func (f OSGiConfigFactory) saveWithChanged(name string, props map[string]any) (string, bool, error) {
+	config, err := f.Find(name)
+	if err != nil {
+		return "", false, err
+	}
+	if config != nil {
+		changed, err := config.SaveWithChanged(props)
+		return config.Pid(), changed, err
+	}
+	if err := f.create(name, props); err != nil {
+		return "", false, err
+	}
+	return osgi.ConfigFactoryPID(f.factoryPID, name), true, nil
+}
+
+func (f OSGiConfigFactory) create(name string, props map[string]any) error {
+	return f.manager.SaveFactory(f.factoryPID, osgi.ConfigFactoryPID(f.factoryPID, name), props)
+}
+
+func (f OSGiConfigFactory) Delete(name string) error {
+	config, err := f.Find(name)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return fmt.Errorf("%s > config '%s' of factory '%s' cannot be deleted as it does not exist", f.manager.instance.ID(), name, f.factoryPID)
+	}
+	return config.Delete()
+}
+
+func (f OSGiConfigFactory) DeleteWithChanged(name string) (bool, error) {
+	config, err := f.Find(name)
+	if err != nil {
+		return false, err
+	}
+	if config == nil {
+		return false, nil
+	}
+	return config.DeleteWithChanged()
+}
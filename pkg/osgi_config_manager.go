@@ -5,6 +5,7 @@ import (
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"github.com/wttech/aemc/pkg/common/fmtx"
+	"github.com/wttech/aemc/pkg/common/stringsx"
 	"github.com/wttech/aemc/pkg/osgi"
 	"golang.org/x/exp/maps"
 	"io"
@@ -107,6 +108,21 @@ func (cm *OSGiConfigManager) Save(pid string, props map[string]any) error {
 	return nil
 }
 
+// SaveFactory creates named factory config (PID 'factoryPid~name'); console creates factory instance only when factory PID is passed
+func (cm *OSGiConfigManager) SaveFactory(factoryPID string, pid string, props map[string]any) error {
+	log.Infof("%s > saving config '%s' of factory '%s'", cm.instance.ID(), pid, factoryPID)
+	request := cm.instance.http.RequestFormData(saveConfigProps(props))
+	request.FormData.Set("factoryPid", factoryPID)
+	resp, err := request.Post(fmt.Sprintf("%s/%s", ConfigMgrPath, pid))
+	if err != nil {
+		return fmt.Errorf("%s > cannot save config '%s' of factory '%s': %w", cm.instance.ID(), pid, factoryPID, err)
+	} else if resp.IsError() {
+		return fmt.Errorf("%s > cannot save config '%s' of factory '%s': %s", cm.instance.ID(), pid, factoryPID, resp.Status())
+	}
+	log.Infof("%s > saved config '%s' of factory '%s'", cm.instance.ID(), pid, factoryPID)
+	return nil
+}
+
 func saveConfigProps(props map[string]any) map[string]any {
 	result := map[string]any{}
 	maps.Copy(result, props)
@@ -133,6 +149,79 @@ func (cm *OSGiConfigManager) Delete(pid string) error {
 	return nil
 }
 
+// ApplyDir saves configs from files located in dir (or in its subdirs matching instance run modes);
+// configs having PID with one of prune prefixes but not present in dir are deleted
+func (cm *OSGiConfigManager) ApplyDir(dir string, prunePrefixes []string) (*osgi.ConfigApplyReport, error) {
+	runModes := cm.instance.RunModes()
+	log.Infof("%s > applying configs from dir '%s' (run modes: %s)", cm.instance.ID(), dir, strings.Join(runModes, ","))
+	files, err := osgi.ReadConfigDir(dir, runModes)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot apply configs from dir '%s': %w", cm.instance.ID(), dir, err)
+	}
+	report := &osgi.ConfigApplyReport{Dir: dir}
+	var applied []string
+	for _, file := range files {
+		pid := file.PID
+		var changed bool
+		if factoryPID, name, ok := strings.Cut(file.PID, osgi.ConfigFactoryDelimiter); ok {
+			pid, changed, err = cm.Factory(factoryPID).saveWithChanged(name, file.Props)
+		} else {
+			changed, err = cm.ByPID(file.PID).SaveWithChanged(file.Props)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s > cannot apply config file '%s': %w", cm.instance.ID(), file.Path, err)
+		}
+		action := osgi.ConfigApplyUnchanged
+		if changed {
+			action = osgi.ConfigApplyChanged
+		}
+		report.Results = append(report.Results, osgi.ConfigApplyResult{PID: file.PID, File: file.Path, Action: action})
+		applied = append(applied, pid)
+	}
+	if len(prunePrefixes) > 0 {
+		pidList, err := cm.listPIDs()
+		if err != nil {
+			return nil, err
+		}
+		for _, pid := range pidList.Prunable(applied, prunePrefixes) {
+			deleted, err := cm.ByPID(pid).DeleteWithChanged()
+			if err != nil {
+				return nil, err
+			}
+			if deleted {
+				report.Results = append(report.Results, osgi.ConfigApplyResult{PID: pid, Action: osgi.ConfigApplyDeleted})
+			}
+		}
+	}
+	log.Infof("%s > applied configs from dir '%s'", cm.instance.ID(), dir)
+	return report, nil
+}
+
+// Export saves configs having PIDs matching pattern as files in dir
+func (cm *OSGiConfigManager) Export(pidPattern string, dir string, format string) ([]string, error) {
+	if format != osgi.ConfigExportFormatJSON {
+		return nil, fmt.Errorf("%s > cannot export configs as format '%s' is not supported (expected '%s')", cm.instance.ID(), format, osgi.ConfigExportFormatJSON)
+	}
+	log.Infof("%s > exporting configs matching '%s' to dir '%s'", cm.instance.ID(), pidPattern, dir)
+	list, err := cm.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, config := range list.List {
+		if !stringsx.Match(config.PID, pidPattern) {
+			continue
+		}
+		file, err := osgi.WriteConfigFile(dir, config)
+		if err != nil {
+			return nil, fmt.Errorf("%s > cannot export config '%s': %w", cm.instance.ID(), config.PID, err)
+		}
+		files = append(files, file)
+	}
+	log.Infof("%s > exported configs matching '%s' to dir '%s' (%d)", cm.instance.ID(), pidPattern, dir, len(files))
+	return files, nil
+}
+
 const (
 	ConfigMgrPath = "/system/console/configMgr"
 )
@@ -0,0 +1,137 @@
+package pkg_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestOSGiConfigManagerApplyDirPrune(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	factoryPID := "org.apache.sling.commons.log.LogManager.factory.config"
+	generatedPID := factoryPID + ".4f9a3c0e-6d8b-4d4e-9a53-2b1f0c7e8d11"
+	configs := map[string]map[string]any{
+		"com.acme.Service": {"enabled": true},
+		"com.acme.Unused":  {"enabled": false},
+		generatedPID:       {"level": "info"},
+	}
+	server, mutex, _ := newConfigMgrServer(a, configs)
+	defer server.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"com.acme.Service.cfg.json":                                   `{"enabled": true}`,
+		factoryPID + "~4f9a3c0e-6d8b-4d4e-9a53-2b1f0c7e8d11.cfg.json": `{"level": "info"}`,
+	}
+	for name, content := range files {
+		a.NoError(os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	instance := pkg.DefaultAEM().InstanceManager().New("local_author", server.URL, "admin", "admin")
+	report, err := instance.OSGI().ConfigManager().ApplyDir(dir, []string{"com.acme.", factoryPID})
+	a.NoError(err)
+	a.True(report.Changed())
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	a.Contains(configs, generatedPID)
+	a.Contains(configs, "com.acme.Service")
+	a.NotContains(configs, "com.acme.Unused")
+}
+
+func TestOSGiConfigManagerApplyDirTwice(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	server, _, posts := newConfigMgrServer(a, map[string]map[string]any{})
+	defer server.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"com.acme.Service.cfg.json": `{"enabled": true, "port:Integer": 8080, "ratio": 1.5, "names": ["a", "b"], "ports": [1, 2]}`,
+		"com.acme.Legacy.config":    "enabled=B\"true\"\nport=I\"8080\"\nnames=[\"a\",\"b\"]\n",
+	}
+	for name, content := range files {
+		a.NoError(os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	instance := pkg.DefaultAEM().InstanceManager().New("local_author", server.URL, "admin", "admin")
+	report, err := instance.OSGI().ConfigManager().ApplyDir(dir, []string{})
+	a.NoError(err)
+	a.True(report.Changed())
+	a.Equal(2, posts())
+
+	report, err = instance.OSGI().ConfigManager().ApplyDir(dir, []string{})
+	a.NoError(err)
+	a.False(report.Changed())
+	a.Equal(2, posts())
+}
+
+// newConfigMgrServer mimics Felix console configuration manager; saved values are rendered as strings like for configs without metatype
+func newConfigMgrServer(a *assert.Assertions, configs map[string]map[string]any) (*httptest.Server, *sync.Mutex, func() int) {
+	var mutex sync.Mutex
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		pid := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, pkg.ConfigMgrPath), "/")
+		switch {
+		case r.Method == http.MethodGet && pid == "":
+			var pids []map[string]any
+			for id := range configs {
+				pids = append(pids, map[string]any{"id": id, "has_config": true})
+			}
+			data, _ := json.Marshal(map[string]any{"pids": pids})
+			_, _ = fmt.Fprintf(w, "<script>var configData = %s;</script>", data)
+		case r.Method == http.MethodGet && strings.HasSuffix(pid, ".json"):
+			props, ok := configs[strings.TrimSuffix(pid, ".json")]
+			if !ok {
+				_, _ = w.Write([]byte("[]"))
+				return
+			}
+			properties := map[string]any{}
+			for k, v := range props {
+				if values, ok := v.([]string); ok {
+					properties[k] = map[string]any{"values": values}
+				} else {
+					properties[k] = map[string]any{"value": v}
+				}
+			}
+			data, _ := json.Marshal([]map[string]any{{"pid": strings.TrimSuffix(pid, ".json"), "properties": properties}})
+			_, _ = w.Write(data)
+		case r.Method == http.MethodPost:
+			a.NoError(r.ParseForm())
+			posts++
+			if r.PostForm.Get("delete") != "" {
+				delete(configs, pid)
+				return
+			}
+			props := map[string]any{}
+			for _, name := range strings.Split(r.PostForm.Get("propertylist"), ",") {
+				if values := r.PostForm[name]; len(values) > 1 {
+					props[name] = values
+				} else {
+					props[name] = r.PostForm.Get(name)
+				}
+			}
+			configs[pid] = props
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, &mutex, func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return posts
+	}
+}
@@ -59,6 +59,17 @@ func (p Package) Build() error {
 	return p.manager.Build(state.Data.Path)
 }
 
+func (p Package) Download(localFile string) error {
+	state, err := p.State()
+	if err != nil {
+		return err
+	}
+	if !state.Exists {
+		return fmt.Errorf("%s > package '%s' cannot be downloaded as it does not exist", p.manager.instance.ID(), p.PID.String())
+	}
+	return p.manager.Download(state.Data.Path, localFile)
+}
+
 func (p *Package) Install() error {
 	state, err := p.State()
 	if err != nil {
@@ -84,6 +95,29 @@ func (p *Package) InstallWithChanged() (bool, error) {
 	return false, nil
 }
 
+func (p *Package) DryRun() (*pkg.InstallLog, error) {
+	state, err := p.State()
+	if err != nil {
+		return nil, err
+	}
+	if !state.Exists {
+		return nil, fmt.Errorf("%s > package '%s' cannot be dry-run as it does not exist", p.manager.instance.ID(), p.PID.String())
+	}
+	return p.manager.DryRun(state.Data.Path)
+}
+
+// InstallLog reads log saved by the recent installation of the package
+func (p Package) InstallLog() (*pkg.InstallLog, error) {
+	state, err := p.State()
+	if err != nil {
+		return nil, err
+	}
+	if !state.Exists {
+		return nil, nil
+	}
+	return p.manager.InstallLog(state.Data.Path)
+}
+
 func (p *Package) Uninstall() error {
 	state, err := p.State()
 	if err != nil {
@@ -1,15 +1,25 @@
 package pkg
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
+	"github.com/wttech/aemc/pkg/common"
 	"github.com/wttech/aemc/pkg/common/filex"
 	"github.com/wttech/aemc/pkg/common/fmtx"
 	"github.com/wttech/aemc/pkg/common/osx"
+	"github.com/wttech/aemc/pkg/common/pathx"
 	"github.com/wttech/aemc/pkg/common/stringsx"
+	"github.com/wttech/aemc/pkg/common/timex"
 	"github.com/wttech/aemc/pkg/pkg"
+	"io"
+	"net/http"
+	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -19,6 +29,11 @@ type PackageManager struct {
 	SnapshotDeploySkipping bool
 	SnapshotPatterns       []string
 	ToggledWorkflows       []string
+
+	InstallRecursive          bool
+	InstallACHandling         string
+	InstallAutosave           int
+	InstallDependencyHandling string
 }
 
 func NewPackageManager(res *Instance) *PackageManager {
@@ -30,6 +45,11 @@ func NewPackageManager(res *Instance) *PackageManager {
 		SnapshotDeploySkipping: cv.GetBool("instance.package.snapshot_deploy_skipping"),
 		SnapshotPatterns:       cv.GetStringSlice("instance.package.snapshot_patterns"),
 		ToggledWorkflows:       cv.GetStringSlice("instance.package.toggled_workflows"),
+
+		InstallRecursive:          cv.GetBool("instance.package.install.recursive"),
+		InstallACHandling:         cv.GetString("instance.package.install.ac_handling"),
+		InstallAutosave:           cv.GetInt("instance.package.install.autosave"),
+		InstallDependencyHandling: cv.GetString("instance.package.install.dependency_handling"),
 	}
 }
 
@@ -83,6 +103,19 @@ func (pm *PackageManager) List() (*pkg.List, error) {
 	return res, nil
 }
 
+// ListBy returns packages matching the filter
+func (pm *PackageManager) ListBy(filter pkg.ListFilter) (*pkg.List, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	list, err := pm.List()
+	if err != nil {
+		return nil, err
+	}
+	items := filter.Apply(list.List)
+	return &pkg.List{List: items, Total: len(items)}, nil
+}
+
 func (pm *PackageManager) Find(pid string) (*pkg.ListItem, error) {
 	item, err := pm.findInternal(pid)
 	if err != nil {
@@ -96,7 +129,19 @@ func (pm *PackageManager) findInternal(pid string) (*pkg.ListItem, error) {
 	if err != nil {
 		return nil, err
 	}
-	resp, err := pm.instance.http.Request().SetQueryParam("name", pidConfig.Name).Get(ListJson)
+	items, err := pm.listByName(pidConfig.Name)
+	if err != nil {
+		return nil, err
+	}
+	item, ok := lo.Find(items, func(p pkg.ListItem) bool { return p.PID == pid })
+	if ok {
+		return &item, nil
+	}
+	return nil, nil
+}
+
+func (pm *PackageManager) listByName(name string) ([]pkg.ListItem, error) {
+	resp, err := pm.instance.http.Request().SetQueryParam("name", name).Get(ListJson)
 	if err != nil {
 		return nil, fmt.Errorf("%s > cannot request package list: %w", pm.instance.ID(), err)
 	} else if resp.IsError() {
@@ -106,11 +151,71 @@ func (pm *PackageManager) findInternal(pid string) (*pkg.ListItem, error) {
 	if err = fmtx.UnmarshalJSON(resp.RawBody(), res); err != nil {
 		return nil, fmt.Errorf("%s > cannot parse package list response: %w", pm.instance.ID(), err)
 	}
-	item, ok := lo.Find(res.List, func(p pkg.ListItem) bool { return p.PID == pid })
-	if ok {
-		return &item, nil
+	return res.List, nil
+}
+
+// IsDependencyInstalled checks if any installed package satisfies the dependency (version may be a range)
+func (pm *PackageManager) IsDependencyInstalled(dependency pkg.PID) (bool, error) {
+	items, err := pm.listByName(dependency.Name)
+	if err != nil {
+		return false, fmt.Errorf("%s > cannot check package dependency '%s': %w", pm.instance.ID(), dependency.String(), err)
 	}
-	return nil, nil
+	return lo.SomeBy(items, func(item pkg.ListItem) bool {
+		return item.Installed() && dependency.IsSatisfiedBy(pkg.PID{Group: item.Group, Name: item.Name, Version: item.Version})
+	}), nil
+}
+
+// ResolveDeployOrder reads dependencies of package files and sorts them so that dependencies are deployed first
+func (pm *PackageManager) ResolveDeployOrder(localPaths []string) ([]string, error) {
+	archives, err := pkg.ReadArchives(localPaths)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot resolve package deploy order: %w", pm.instance.ID(), err)
+	}
+	archives = pkg.LatestArchives(archives)
+	var missing []string
+	for _, dependency := range pkg.ExternalDependencies(archives) {
+		installed, err := pm.IsDependencyInstalled(dependency)
+		if err != nil {
+			return nil, err
+		}
+		if !installed {
+			missing = append(missing, dependency.String())
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s > cannot resolve package deploy order as dependencies are missing: %s", pm.instance.ID(), strings.Join(missing, ", "))
+	}
+	sorted, err := pkg.SortArchives(archives)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot resolve package deploy order: %w", pm.instance.ID(), err)
+	}
+	return lo.Map(sorted, func(a pkg.Archive, _ int) string { return a.File }), nil
+}
+
+func (pm *PackageManager) DeployAllWithChanged(localPaths []string) ([]string, error) {
+	return pm.deployAll(localPaths, pm.DeployWithChanged)
+}
+
+func (pm *PackageManager) DeployAll(localPaths []string) ([]string, error) {
+	return pm.deployAll(localPaths, func(localPath string) (bool, error) { return true, pm.Deploy(localPath) })
+}
+
+func (pm *PackageManager) deployAll(localPaths []string, deployer func(localPath string) (bool, error)) ([]string, error) {
+	ordered, err := pm.ResolveDeployOrder(localPaths)
+	if err != nil {
+		return nil, err
+	}
+	var deployed []string
+	for _, localPath := range ordered {
+		changed, err := deployer(localPath)
+		if err != nil {
+			return deployed, err
+		}
+		if changed {
+			deployed = append(deployed, localPath)
+		}
+	}
+	return deployed, nil
 }
 
 func (pm *PackageManager) IsSnapshot(localPath string) bool {
@@ -136,6 +241,128 @@ func (pm *PackageManager) Build(remotePath string) error {
 	return nil
 }
 
+func (pm *PackageManager) CreateWithChanged(pid string, filters []pkg.Filter) (bool, error) {
+	item, err := pm.Find(pid)
+	if err != nil {
+		return false, err
+	}
+	if item == nil {
+		_, err = pm.Create(pid, filters)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	current, err := pm.Filters(item.Path)
+	if err != nil {
+		return false, err
+	}
+	if pkg.FiltersEqual(current, filters) {
+		return false, nil
+	}
+	if err = pm.UpdateFilters(item.Path, pkg.PID{Group: item.Group, Name: item.Name, Version: item.Version}, filters); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (pm *PackageManager) Create(pid string, filters []pkg.Filter) (string, error) {
+	pidConfig, err := pkg.ParsePID(pid)
+	if err != nil {
+		return "", err
+	}
+	for _, filter := range filters {
+		if err := filter.Validate(); err != nil {
+			return "", fmt.Errorf("%s > cannot create package '%s': %w", pm.instance.ID(), pid, err)
+		}
+	}
+	log.Infof("%s > creating package '%s'", pm.instance.ID(), pid)
+	response, err := pm.instance.http.Request().
+		SetFormData(map[string]string{
+			"packageName":    pidConfig.Name,
+			"groupName":      pidConfig.Group,
+			"packageVersion": pidConfig.Version,
+		}).
+		Post(ServiceJsonPath + pm.remotePath(*pidConfig) + "?cmd=create")
+	if err != nil {
+		return "", fmt.Errorf("%s > cannot create package '%s': %w", pm.instance.ID(), pid, err)
+	} else if response.IsError() {
+		return "", fmt.Errorf("%s > cannot create package '%s': %s", pm.instance.ID(), pid, response.Status())
+	}
+	var status pkg.CommandResult
+	if err = fmtx.UnmarshalJSON(response.RawBody(), &status); err != nil {
+		return "", fmt.Errorf("%s > cannot create package '%s'; cannot parse response: %w", pm.instance.ID(), pid, err)
+	}
+	if !status.Success {
+		return "", fmt.Errorf("%s > cannot create package '%s'; unexpected status: %s", pm.instance.ID(), pid, status.Message)
+	}
+	remotePath := status.Path
+	if remotePath == "" {
+		remotePath = pm.remotePath(*pidConfig)
+	}
+	if err = pm.UpdateFilters(remotePath, *pidConfig, filters); err != nil {
+		return "", err
+	}
+	log.Infof("%s > created package '%s'", pm.instance.ID(), pid)
+	return remotePath, nil
+}
+
+func (pm *PackageManager) remotePath(pid pkg.PID) string {
+	fileName := pid.Name + ".zip"
+	if pid.Version != "" {
+		fileName = fmt.Sprintf("%s-%s.zip", pid.Name, pid.Version)
+	}
+	return fmt.Sprintf("%s/%s/%s", PackagesRoot, pid.Group, fileName)
+}
+
+func (pm *PackageManager) Filters(remotePath string) ([]pkg.Filter, error) {
+	response, err := pm.instance.http.Request().Get(remotePath + DefinitionFilterPath + ".1.json")
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot read filters of package '%s': %w", pm.instance.ID(), remotePath, err)
+	} else if response.StatusCode() == http.StatusNotFound {
+		return []pkg.Filter{}, nil
+	} else if response.IsError() {
+		return nil, fmt.Errorf("%s > cannot read filters of package '%s': %s", pm.instance.ID(), remotePath, response.Status())
+	}
+	var node map[string]any
+	if err = fmtx.UnmarshalJSON(response.RawBody(), &node); err != nil {
+		return nil, fmt.Errorf("%s > cannot parse filters of package '%s': %w", pm.instance.ID(), remotePath, err)
+	}
+	return pkg.FiltersFromDefinition(node), nil
+}
+
+func (pm *PackageManager) UpdateFilters(remotePath string, pid pkg.PID, filters []pkg.Filter) error {
+	log.Infof("%s > updating filters of package '%s'", pm.instance.ID(), remotePath)
+	filterJSON, err := json.Marshal(filters)
+	if err != nil {
+		return fmt.Errorf("%s > cannot update filters of package '%s'; cannot serialize them: %w", pm.instance.ID(), remotePath, err)
+	}
+	response, err := pm.instance.http.Request().
+		SetMultipartFormData(map[string]string{
+			"path":        remotePath,
+			"packageName": pid.Name,
+			"groupName":   pid.Group,
+			"version":     pid.Version,
+			"filter":      string(filterJSON),
+			"_charset_":   "UTF-8",
+		}).
+		Post(UpdatePath)
+	if err != nil {
+		return fmt.Errorf("%s > cannot update filters of package '%s': %w", pm.instance.ID(), remotePath, err)
+	} else if response.IsError() {
+		return fmt.Errorf("%s > cannot update filters of package '%s': %s", pm.instance.ID(), remotePath, response.Status())
+	}
+	var status pkg.CommandResult
+	if err = fmtx.UnmarshalJSON(response.RawBody(), &status); err != nil {
+		return fmt.Errorf("%s > cannot update filters of package '%s'; cannot parse response: %w", pm.instance.ID(), remotePath, err)
+	}
+	if !status.Success {
+		return fmt.Errorf("%s > cannot update filters of package '%s'; unexpected status: %s", pm.instance.ID(), remotePath, status.Message)
+	}
+	log.Infof("%s > updated filters of package '%s'", pm.instance.ID(), remotePath)
+	return nil
+}
+
 func (pm *PackageManager) UploadWithChanged(localPath string) (bool, error) {
 	if pm.IsSnapshot(localPath) {
 		_, err := pm.Upload(localPath)
@@ -185,26 +412,201 @@ func (pm *PackageManager) Upload(localPath string) (string, error) {
 }
 
 func (pm *PackageManager) Install(remotePath string) error {
+	return pm.install(remotePath)
+}
+
+// install runs package installation and saves its log in a file named after the remote package path
+func (pm *PackageManager) install(remotePath string) error {
+	if err := pm.ValidateInstallOpts(); err != nil {
+		return err
+	}
 	log.Infof("%s > installing package '%s'", pm.instance.ID(), remotePath)
-	response, err := pm.instance.http.Request().
-		SetFormData(map[string]string{"cmd": "install"}).
-		Post(ServiceJsonPath + remotePath)
+	text, err := pm.installCommand(remotePath, "install")
 	if err != nil {
 		return fmt.Errorf("%s > cannot install package '%s': %w", pm.instance.ID(), remotePath, err)
-	} else if response.IsError() {
-		return fmt.Errorf("%s > cannot install package '%s': '%s'", pm.instance.ID(), remotePath, response.Status())
 	}
-	var status pkg.CommandResult
-	if err = fmtx.UnmarshalJSON(response.RawBody(), &status); err != nil {
-		return fmt.Errorf("%s > cannot install package '%s'; cannot parse response: %w", pm.instance.ID(), remotePath, err)
+	if err = filex.WriteString(pm.installLogFile(remotePath), text); err != nil {
+		return fmt.Errorf("%s > cannot save install log of package '%s': %w", pm.instance.ID(), remotePath, err)
 	}
-	if !status.Success {
-		return fmt.Errorf("%s > cannot install package '%s'; unexpected status: %s", pm.instance.ID(), remotePath, status.Message)
+	installLog := pkg.ParseInstallLog(text)
+	if installLog.Failed() {
+		return pkg.InstallError{InstanceID: pm.instance.ID(), RemotePath: remotePath, Log: installLog}
 	}
 	log.Infof("%s > installed package '%s'", pm.instance.ID(), remotePath)
 	return nil
 }
 
+// DryRun checks what would be changed by package installation without applying any changes
+func (pm *PackageManager) DryRun(remotePath string) (*pkg.InstallLog, error) {
+	if err := pm.ValidateInstallOpts(); err != nil {
+		return nil, err
+	}
+	log.Infof("%s > dry-running package '%s'", pm.instance.ID(), remotePath)
+	text, err := pm.installCommand(remotePath, "dryrun")
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot dry-run package '%s': %w", pm.instance.ID(), remotePath, err)
+	}
+	installLog := pkg.ParseInstallLog(text)
+	log.Infof("%s > dry-ran package '%s'", pm.instance.ID(), remotePath)
+	return &installLog, nil
+}
+
+// DeployDryRun checks what would be changed by package installation.
+// Already uploaded package is checked as is (never overridden), otherwise it is uploaded temporarily and deleted afterwards.
+func (pm *PackageManager) DeployDryRun(localPath string) (*pkg.InstallLog, bool, error) {
+	p, err := pm.ByFile(localPath)
+	if err != nil {
+		return nil, false, err
+	}
+	state, err := p.State()
+	if err != nil {
+		return nil, false, err
+	}
+	if state.Exists {
+		log.Infof("%s > package '%s' already uploaded so dry-running existing one at path '%s'", pm.instance.ID(), p.PID.String(), state.Data.Path)
+		installLog, err := pm.DryRun(state.Data.Path)
+		return installLog, false, err
+	}
+	remotePath, err := pm.Upload(localPath)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err := pm.Delete(remotePath); err != nil {
+			log.Warn(err)
+		}
+	}()
+	installLog, err := pm.DryRun(remotePath)
+	return installLog, true, err
+}
+
+func (pm *PackageManager) installCommand(remotePath string, command string) (string, error) {
+	props := map[string]any{
+		"cmd":       command,
+		"recursive": pm.InstallRecursive,
+	}
+	if pm.InstallAutosave > 0 {
+		props["autosave"] = pm.InstallAutosave
+	}
+	if pm.InstallACHandling != "" {
+		props["acHandling"] = pm.InstallACHandling
+	}
+	if pm.InstallDependencyHandling != "" {
+		props["dependencyHandling"] = pm.InstallDependencyHandling
+	}
+	response, err := pm.instance.http.RequestFormData(props).Post(ServiceHtmlPath + remotePath)
+	if err != nil {
+		return "", err
+	} else if response.IsError() {
+		return "", fmt.Errorf("%s", response.Status())
+	}
+	defer response.RawBody().Close()
+	text, err := io.ReadAll(response.RawBody())
+	if err != nil {
+		return "", fmt.Errorf("cannot read response: %w", err)
+	}
+	return string(text), nil
+}
+
+func (pm *PackageManager) ValidateInstallOpts() error {
+	if pm.InstallACHandling != "" && !lo.Contains(pkg.ACHandlings(), pm.InstallACHandling) {
+		return fmt.Errorf("%s > package install option 'acHandling' has unsupported value '%s' (expected one of: %s)", pm.instance.ID(), pm.InstallACHandling, strings.Join(pkg.ACHandlings(), ", "))
+	}
+	if pm.InstallDependencyHandling != "" && !lo.Contains(pkg.DependencyHandlings(), pm.InstallDependencyHandling) {
+		return fmt.Errorf("%s > package install option 'dependencyHandling' has unsupported value '%s' (expected one of: %s)", pm.instance.ID(), pm.InstallDependencyHandling, strings.Join(pkg.DependencyHandlings(), ", "))
+	}
+	return nil
+}
+
+var installLogFileName = regexp.MustCompile(`[^A-Za-z0-9._/-]`)
+
+// installLogFile is unique per package as remote path contains group, name and version (e.g. '/etc/packages/my-group/my-app-1.0.0.zip')
+func (pm *PackageManager) installLogFile(remotePath string) string {
+	return fmt.Sprintf("%s/package/install/%s.log", pm.instance.LockDir(), installLogFileName.ReplaceAllString(strings.TrimPrefix(path.Clean("/"+remotePath), "/"), "_"))
+}
+
+// InstallLog reads log saved by the recent installation of package (by its remote or local path)
+func (pm *PackageManager) InstallLog(path string) (*pkg.InstallLog, error) {
+	if !strings.HasPrefix(path, PackagesRoot+"/") && pathx.Exists(path) {
+		p, err := pm.ByFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return p.InstallLog()
+	}
+	file := pm.installLogFile(path)
+	if !pathx.Exists(file) {
+		return nil, nil
+	}
+	text, err := filex.ReadString(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot read install log of package '%s': %w", pm.instance.ID(), path, err)
+	}
+	installLog := pkg.ParseInstallLog(text)
+	return &installLog, nil
+}
+
+func (pm *PackageManager) Download(remotePath string, localFile string) error {
+	log.Infof("%s > downloading package '%s' to file '%s'", pm.instance.ID(), remotePath, localFile)
+	fileTmp := localFile + ".tmp"
+	if err := pathx.DeleteIfExists(fileTmp); err != nil {
+		return fmt.Errorf("%s > cannot delete temporary file for package '%s' downloaded to '%s': %w", pm.instance.ID(), remotePath, localFile, err)
+	}
+	defer func() { _ = pathx.DeleteIfExists(fileTmp) }()
+	response, err := pm.instance.http.Request().Get(remotePath)
+	if err != nil {
+		return fmt.Errorf("%s > cannot download package '%s': %w", pm.instance.ID(), remotePath, err)
+	}
+	defer response.RawBody().Close()
+	if response.IsError() {
+		return fmt.Errorf("%s > cannot download package '%s': %s", pm.instance.ID(), remotePath, response.Status())
+	}
+	if err = filex.WriteReader(fileTmp, response.RawBody()); err != nil {
+		return fmt.Errorf("%s > cannot download package '%s': %w", pm.instance.ID(), remotePath, err)
+	}
+	if _, err = pkg.ReadPIDFromZIP(fileTmp); err != nil {
+		return fmt.Errorf("%s > cannot download package '%s'; downloaded file is not a valid package: %w", pm.instance.ID(), remotePath, err)
+	}
+	if err = os.Rename(fileTmp, localFile); err != nil {
+		return fmt.Errorf("%s > cannot move downloaded package from temporary path '%s' to target one '%s': %w", pm.instance.ID(), fileTmp, localFile, err)
+	}
+	log.Infof("%s > downloaded package '%s' to file '%s'", pm.instance.ID(), remotePath, localFile)
+	return nil
+}
+
+// Export creates temporary package covering the given filters, builds it then downloads it to the local file
+func (pm *PackageManager) Export(filters []pkg.Filter, localFile string) error {
+	remotePath, err := pm.Create(pm.temporaryPID(), filters)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := pm.Delete(remotePath); err != nil {
+			log.Warn(err)
+		}
+	}()
+	if err = pm.Build(remotePath); err != nil {
+		return err
+	}
+	return pm.Download(remotePath, localFile)
+}
+
+// Import deploys package from the local file then deletes it as it is no longer needed on the instance
+func (pm *PackageManager) Import(localFile string) error {
+	if err := pm.Deploy(localFile); err != nil {
+		return err
+	}
+	p, err := pm.ByFile(localFile)
+	if err != nil {
+		return err
+	}
+	return p.Delete()
+}
+
+func (pm *PackageManager) temporaryPID() string {
+	return fmt.Sprintf("%s:%s-%s:%s", TemporaryGroup, TemporaryNamePrefix, timex.FileTimestampForNow(), TemporaryVersion)
+}
+
 func (pm *PackageManager) DeployWithChanged(localPath string) (bool, error) {
 	if pm.IsSnapshot(localPath) {
 		return pm.deploySnapshot(localPath)
@@ -269,14 +671,130 @@ func (pm *PackageManager) Deploy(localPath string) error {
 	if err != nil {
 		return err
 	}
-	return pm.instance.workflowManager.ToggleLaunchers(pm.ToggledWorkflows, func() error {
-		return pm.Install(remotePath)
+	if err := pm.instance.workflowManager.ToggleLaunchers(pm.ToggledWorkflows, func() error {
+		return pm.install(remotePath)
+	}); err != nil {
+		return err
+	}
+	return pm.recordDeploy(localPath)
+}
+
+func (pm *PackageManager) historyFile() string {
+	return fmt.Sprintf("%s/package/history.yml", pm.instance.LockDir())
+}
+
+// History returns records of packages deployed and rolled back on the instance
+func (pm *PackageManager) History() (*pkg.DeployHistory, error) {
+	result := &pkg.DeployHistory{Records: []pkg.DeployRecord{}}
+	file := pm.historyFile()
+	if !pathx.Exists(file) {
+		return result, nil
+	}
+	if err := fmtx.UnmarshalFile(file, result); err != nil {
+		return nil, fmt.Errorf("%s > cannot read package deploy history: %w", pm.instance.ID(), err)
+	}
+	return result, nil
+}
+
+func (pm *PackageManager) appendHistory(record pkg.DeployRecord) error {
+	history, err := pm.History()
+	if err != nil {
+		return err
+	}
+	history.Records = append(history.Records, record)
+	if err := fmtx.MarshalToFile(pm.historyFile(), history); err != nil {
+		return fmt.Errorf("%s > cannot save package deploy history: %w", pm.instance.ID(), err)
+	}
+	return nil
+}
+
+func (pm *PackageManager) recordDeploy(localPath string) error {
+	pid, err := pkg.ReadPIDFromZIP(localPath)
+	if err != nil {
+		return err
+	}
+	checksum, err := filex.ChecksumFile(localPath)
+	if err != nil {
+		return err
+	}
+	return pm.appendHistory(pkg.DeployRecord{
+		Action:   pkg.HistoryActionDeploy,
+		Instance: pm.instance.ID(),
+		PID:      pid.String(),
+		File:     pathx.Abs(localPath),
+		Checksum: checksum,
+		Time:     time.Now(),
 	})
 }
 
+// Rollback uninstalls currently installed version of the package and reinstalls the previously installed one
+func (pm *PackageManager) Rollback(pid string) (*pkg.ListItem, *pkg.ListItem, error) {
+	pidConfig, err := pkg.ParsePID(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	current, previous, err := pm.rollbackVersions(*pidConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s > cannot rollback package '%s': %w", pm.instance.ID(), pid, err)
+	}
+	log.Infof("%s > rolling back package '%s' to '%s'", pm.instance.ID(), current.PID, previous.PID)
+	if err := pm.Uninstall(current.Path); err != nil {
+		return nil, nil, err
+	}
+	if err := pm.instance.workflowManager.ToggleLaunchers(pm.ToggledWorkflows, func() error {
+		return pm.Install(previous.Path)
+	}); err != nil {
+		return nil, nil, err
+	}
+	if err := pm.appendHistory(pkg.DeployRecord{
+		Action:   pkg.HistoryActionRollback,
+		Instance: pm.instance.ID(),
+		PID:      previous.PID,
+		Time:     time.Now(),
+	}); err != nil {
+		return nil, nil, err
+	}
+	log.Infof("%s > rolled back package '%s' to '%s'", pm.instance.ID(), current.PID, previous.PID)
+	return current, previous, nil
+}
+
+// rollbackVersions finds currently installed package version and the one installed before it (preferring order from deploy history)
+func (pm *PackageManager) rollbackVersions(pid pkg.PID) (*pkg.ListItem, *pkg.ListItem, error) {
+	items, err := pm.listByName(pid.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	items = lo.Filter(items, func(i pkg.ListItem, _ int) bool { return i.Group == pid.Group && i.Name == pid.Name })
+	installed := lo.Filter(items, func(i pkg.ListItem, _ int) bool { return i.Installed() })
+	if len(installed) == 0 {
+		return nil, nil, fmt.Errorf("package is not installed")
+	}
+	current := lo.MaxBy(installed, func(a pkg.ListItem, b pkg.ListItem) bool { return a.LastUnpacked > b.LastUnpacked })
+	others := lo.Filter(items, func(i pkg.ListItem, _ int) bool { return i.Version != current.Version })
+	history, err := pm.History()
+	if err != nil {
+		return nil, nil, err
+	}
+	stack := history.VersionStack(pid)
+	if currentIndex := lo.LastIndexOf(stack, current.PID); currentIndex >= 0 {
+		for i := currentIndex - 1; i >= 0; i-- {
+			previous, ok := lo.Find(others, func(item pkg.ListItem) bool { return item.PID == stack[i] })
+			if ok {
+				return &current, &previous, nil
+			}
+		}
+	}
+	previouslyInstalled := lo.Filter(others, func(i pkg.ListItem, _ int) bool { return i.Installed() })
+	if len(previouslyInstalled) == 0 {
+		return nil, nil, fmt.Errorf("no previously installed version of package found")
+	}
+	previous := lo.MaxBy(previouslyInstalled, func(a pkg.ListItem, b pkg.ListItem) bool { return a.LastUnpacked > b.LastUnpacked })
+	return &current, &previous, nil
+}
+
 func (pm *PackageManager) deployLock(file string, checksum string) osx.Lock[packageDeployLock] {
 	name := filepath.Base(file)
-	return osx.NewLock(fmt.Sprintf("%s/package/deploy/%s.yml", pm.instance.local.LockDir(), name), func() (packageDeployLock, error) {
+	return osx.NewLock(fmt.Sprintf("%s/package/deploy/%s.yml", pm.instance.LockDir(), name), func() (packageDeployLock, error) {
 		return packageDeployLock{Deployed: time.Now(), Checksum: checksum}, nil
 	})
 }
@@ -307,6 +825,26 @@ func (pm *PackageManager) Uninstall(remotePath string) error {
 	return nil
 }
 
+// PurgeBy deletes packages selected by the policy (installed content is left intact)
+func (pm *PackageManager) PurgeBy(policy pkg.PurgePolicy, dryRun bool) (*pkg.PurgeResult, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	list, err := pm.List()
+	if err != nil {
+		return nil, err
+	}
+	candidates := policy.Candidates(list.List, func(i pkg.ListItem) bool { return pm.IsSnapshot(i.Path) }, time.Now())
+	if !dryRun {
+		for _, item := range candidates {
+			if err := pm.Delete(item.Path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return pkg.NewPurgeResult(candidates, dryRun), nil
+}
+
 func (pm *PackageManager) Delete(remotePath string) error {
 	log.Infof("%s > deleting package '%s'", pm.instance.ID(), remotePath)
 	response, err := pm.instance.http.Request().
@@ -335,4 +873,12 @@ const (
 	ServiceHtmlPath = ServicePath + "/.html"
 	ListJson        = MgrPath + "/list.jsp"
 	IndexPath       = MgrPath + "/index.jsp"
+	UpdatePath      = MgrPath + "/update.jsp"
+
+	PackagesRoot         = "/etc/packages"
+	DefinitionFilterPath = "/jcr:content/vlt:definition/filter"
+
+	TemporaryGroup      = common.AppId
+	TemporaryNamePrefix = "content"
+	TemporaryVersion    = "1.0.0"
 )
@@ -6,6 +6,8 @@ import (
 	"github.com/samber/lo"
 	"github.com/wttech/aemc/pkg/common/fmtx"
 	"github.com/wttech/aemc/pkg/common/intsx"
+	"github.com/wttech/aemc/pkg/common/timex"
+	"time"
 )
 
 type List struct {
@@ -47,18 +49,26 @@ func (pl List) String() string {
 }
 
 func (pl List) MarshalText() string {
-	return fmtx.TblRows("list", false, []string{"group", "name", "version", "size", "installed", "built"}, lo.Map(pl.List, func(item ListItem, _ int) map[string]any {
+	return fmtx.TblRows("list", false, []string{"group", "name", "version", "size", "installed", "built", "modified"}, lo.Map(pl.List, func(item ListItem, _ int) map[string]any {
 		return map[string]any{
 			"group":     item.Group,
 			"name":      item.Name,
 			"version":   item.Version,
 			"size":      humanize.Bytes(uint64(item.Size)),
-			"installed": item.Installed(), // TODO date or 'not yet'
-			"built":     item.Built(),     // TODO date or 'not yet'
+			"installed": listItemDate(item.LastUnpacked),
+			"built":     listItemDate(item.LastWrapped),
+			"modified":  listItemDate(item.LastModified),
 		}
 	}))
 }
 
+func listItemDate(millis int) string {
+	if millis <= 0 {
+		return "not yet"
+	}
+	return timex.Human(time.UnixMilli(int64(millis)))
+}
+
 type CommandResult struct {
 	Success bool   `json:"success"`
 	Message string `json:"msg"`
@@ -0,0 +1,240 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common"
+	"github.com/wttech/aemc/pkg/common/filex"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"github.com/wttech/aemc/pkg/common/pathx"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	VltConfig = VltPath + "/config.xml"
+)
+
+// Assembly describes package to be built locally from checked-out repository content (without communicating with AEM instance)
+type Assembly struct {
+	SourceDir   string
+	PID         PID
+	Filters     []Filter
+	Description string
+}
+
+// Assemble builds package file; source dir may be a 'jcr_root' dir or its parent (optionally containing 'META-INF/vault/filter.xml')
+func (a Assembly) Assemble(file string) error {
+	jcrRootDir, metaDir := a.sourceDirs()
+	if !pathx.IsDir(jcrRootDir) {
+		return fmt.Errorf("package '%s' cannot be assembled as source dir '%s' does not exist", a.PID.String(), jcrRootDir)
+	}
+	if a.PID.Group == "" || a.PID.Name == "" || a.PID.Version == "" {
+		return fmt.Errorf("package '%s' cannot be assembled as its ID should be in format 'group:name:version'", a.PID.String())
+	}
+	stagingDir, err := os.MkdirTemp(filepath.Dir(pathx.Abs(file)), "tmp-assemble-")
+	if err != nil {
+		return fmt.Errorf("package '%s' cannot be assembled; cannot create temporary dir: %w", a.PID.String(), err)
+	}
+	defer func() { _ = pathx.DeleteIfExists(stagingDir) }()
+	if err := filex.CopyDir(jcrRootDir, filepath.Join(stagingDir, JcrRoot)); err != nil {
+		return fmt.Errorf("package '%s' cannot be assembled; cannot copy content from dir '%s': %w", a.PID.String(), jcrRootDir, err)
+	}
+	filterXML, err := a.filterXML(jcrRootDir, metaDir)
+	if err != nil {
+		return err
+	}
+	vltFiles := map[string]string{
+		VltProperties: a.propertiesXML(),
+		VltFilter:     filterXML,
+		VltConfig:     vltConfigXML,
+	}
+	for path, text := range vltFiles {
+		if err := filex.WriteString(filepath.Join(stagingDir, path), text); err != nil {
+			return fmt.Errorf("package '%s' cannot be assembled; cannot write file '%s': %w", a.PID.String(), path, err)
+		}
+	}
+	if err := pathx.DeleteIfExists(file); err != nil {
+		return fmt.Errorf("package '%s' cannot be assembled; cannot delete previous file '%s': %w", a.PID.String(), file, err)
+	}
+	return filex.Archive(stagingDir, file)
+}
+
+func (a Assembly) sourceDirs() (string, string) {
+	nested := filepath.Join(a.SourceDir, JcrRoot)
+	if pathx.IsDir(nested) {
+		return nested, filepath.Join(a.SourceDir, MetaPath)
+	}
+	return a.SourceDir, filepath.Join(filepath.Dir(a.SourceDir), MetaPath)
+}
+
+func (a Assembly) filterXML(jcrRootDir string, metaDir string) (string, error) {
+	if len(a.Filters) > 0 {
+		return FilterXML(a.Filters), nil
+	}
+	existingFile := filepath.Join(metaDir, VltDir, filepath.Base(VltFilter))
+	if pathx.Exists(existingFile) {
+		return filex.ReadString(existingFile)
+	}
+	roots, err := DeriveFilterRoots(jcrRootDir)
+	if err != nil {
+		return "", fmt.Errorf("package '%s' cannot be assembled; cannot derive filters from dir '%s': %w", a.PID.String(), jcrRootDir, err)
+	}
+	if len(roots) == 0 {
+		return "", fmt.Errorf("package '%s' cannot be assembled as no content could be found in dir '%s' to derive filters from; specify them explicitly", a.PID.String(), jcrRootDir)
+	}
+	filters := lo.Map(NewFilters(roots), func(f Filter, _ int) Filter {
+		f.Mode = FilterModeMerge
+		return f
+	})
+	return FilterXML(filters), nil
+}
+
+// DeriveFilterRoots determines roots as the first dirs holding content ('.content.xml' or other files) when descending from top-level ones (e.g. '/apps/my-app', '/content/dam/my-site').
+// Top-level dirs (e.g. '/conf') are never considered roots; dirs without any content are skipped.
+func DeriveFilterRoots(jcrRootDir string) ([]string, error) {
+	topDirs, err := subDirNames(jcrRootDir)
+	if err != nil {
+		return nil, err
+	}
+	var roots []string
+	for _, topDir := range topDirs {
+		topRoots, err := deriveFilterRootsIn(jcrRootDir, "/"+topDir)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, topRoots...)
+	}
+	return roots, nil
+}
+
+func deriveFilterRootsIn(jcrRootDir string, parent string) ([]string, error) {
+	subDirs, err := subDirNames(filepath.Join(jcrRootDir, filepath.FromSlash(parent)))
+	if err != nil {
+		return nil, err
+	}
+	var roots []string
+	for _, subDir := range subDirs {
+		root := parent + "/" + subDir
+		hasContent, err := dirHasContent(filepath.Join(jcrRootDir, filepath.FromSlash(root)))
+		if err != nil {
+			return nil, err
+		}
+		if hasContent {
+			roots = append(roots, root)
+			continue
+		}
+		subRoots, err := deriveFilterRootsIn(jcrRootDir, root)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, subRoots...)
+	}
+	return roots, nil
+}
+
+func dirHasContent(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	return lo.SomeBy(entries, func(e os.DirEntry) bool {
+		return !e.IsDir() && (e.Name() == ContentXML || !strings.HasPrefix(e.Name(), "."))
+	}), nil
+}
+
+func subDirNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := lo.FilterMap(entries, func(e os.DirEntry, _ int) (string, bool) {
+		return e.Name(), e.IsDir() && !strings.HasPrefix(e.Name(), ".")
+	})
+	sort.Strings(names)
+	return names, nil
+}
+
+// FilterXML renders filters in format of 'META-INF/vault/filter.xml'
+func FilterXML(filters []Filter) string {
+	bs := bytes.NewBufferString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<workspaceFilter version=\"1.0\">\n")
+	for _, filter := range filters {
+		bs.WriteString(fmt.Sprintf("    <filter root=\"%s\"", fmtx.EscapeXML(filter.Root)))
+		if filter.Mode != "" {
+			bs.WriteString(fmt.Sprintf(" mode=\"%s\"", fmtx.EscapeXML(filter.Mode)))
+		}
+		if len(filter.Rules) == 0 {
+			bs.WriteString("/>\n")
+			continue
+		}
+		bs.WriteString(">\n")
+		for _, rule := range filter.Rules {
+			bs.WriteString(fmt.Sprintf("        <%s pattern=\"%s\"/>\n", rule.Modifier, fmtx.EscapeXML(rule.Pattern)))
+		}
+		bs.WriteString("    </filter>\n")
+	}
+	bs.WriteString("</workspaceFilter>\n")
+	return bs.String()
+}
+
+func (a Assembly) propertiesXML() string {
+	props := map[string]string{
+		PropGroup:     a.PID.Group,
+		PropName:      a.PID.Name,
+		PropVersion:   a.PID.Version,
+		PropCreatedBy: common.AppId,
+		"created":     time.Now().Format("2006-01-02T15:04:05.000-07:00"),
+		"packageType": "mixed",
+	}
+	if a.Description != "" {
+		props[PropDescription] = a.Description
+	}
+	keys := lo.Keys(props)
+	sort.Strings(keys)
+	bs := bytes.NewBufferString("<?xml version=\"1.0\" encoding=\"utf-8\" standalone=\"no\"?>\n<!DOCTYPE properties SYSTEM \"http://java.sun.com/dtd/properties.dtd\">\n<properties>\n")
+	for _, key := range keys {
+		bs.WriteString(fmt.Sprintf("<entry key=\"%s\">%s</entry>\n", fmtx.EscapeXML(key), fmtx.EscapeXML(props[key])))
+	}
+	bs.WriteString("</properties>\n")
+	return bs.String()
+}
+
+const vltConfigXML = `<?xml version="1.0" encoding="UTF-8"?>
+<vaultfs version="1.1">
+    <aggregates>
+        <aggregate type="file" title="Files"/>
+        <aggregate type="filefolder" title="File Folders"/>
+        <aggregate type="nodetypes" title="Node Types"/>
+        <aggregate type="full" title="Full Coverage Aggregate">
+            <matches>
+                <include nodeType="rep:AccessControl" respectSupertype="true"/>
+                <include nodeType="cq:Widget" respectSupertype="true"/>
+                <include nodeType="cq:EditConfig" respectSupertype="true"/>
+                <include nodeType="cq:WorkflowModel" respectSupertype="true"/>
+                <include nodeType="vlt:FullCoverage" respectSupertype="true"/>
+                <include nodeType="mix:language" respectSupertype="true"/>
+                <include nodeType="sling:OsgiConfig" respectSupertype="true"/>
+            </matches>
+        </aggregate>
+        <aggregate type="generic" title="Folders">
+            <matches>
+                <include nodeType="nt:folder" respectSupertype="true"/>
+            </matches>
+            <contains>
+                <exclude isNode="true"/>
+            </contains>
+        </aggregate>
+        <aggregate type="generic" title="Default"/>
+    </aggregates>
+    <handlers>
+        <handler type="folder"/>
+        <handler type="file"/>
+        <handler type="nodetypes"/>
+        <handler type="generic"/>
+    </handlers>
+</vaultfs>
+`
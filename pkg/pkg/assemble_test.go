@@ -0,0 +1,71 @@
+package pkg_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/pkg"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAssemble(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	sourceDir := t.TempDir()
+	a.Nil(os.MkdirAll(filepath.Join(sourceDir, "jcr_root/apps/my-app/components"), 0755))
+	a.Nil(os.WriteFile(filepath.Join(sourceDir, "jcr_root/apps/my-app/.content.xml"), []byte("<jcr:root/>"), 0644))
+	a.Nil(os.MkdirAll(filepath.Join(sourceDir, "jcr_root/conf/my-site"), 0755))
+	a.Nil(os.WriteFile(filepath.Join(sourceDir, "jcr_root/conf/my-site/.content.xml"), []byte("<jcr:root/>"), 0644))
+
+	file := filepath.Join(t.TempDir(), "my-package.zip")
+	assembly := pkg.Assembly{SourceDir: sourceDir, PID: pkg.PID{Group: "my-group", Name: "my-package", Version: "1.0.0"}}
+	a.Nil(assembly.Assemble(file))
+
+	inspection, err := pkg.Inspect(file)
+	a.Nil(err)
+	a.Equal("my-group:my-package:1.0.0", inspection.PID.String())
+	a.Equal([]pkg.Filter{mergeFilter("/apps/my-app"), mergeFilter("/conf/my-site")}, inspection.Filters)
+	a.Equal(2, inspection.ContentFiles)
+}
+
+func TestAssembleNoContent(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	sourceDir := t.TempDir()
+	a.Nil(os.MkdirAll(filepath.Join(sourceDir, "jcr_root/apps/my-app/components"), 0755))
+
+	file := filepath.Join(t.TempDir(), "my-package.zip")
+	assembly := pkg.Assembly{SourceDir: sourceDir, PID: pkg.PID{Group: "my-group", Name: "my-package", Version: "1.0.0"}}
+	a.NotNil(assembly.Assemble(file))
+}
+
+func TestDeriveFilterRoots(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	jcrRootDir := t.TempDir()
+	files := []string{
+		"content/dam/my-site/.content.xml",
+		"content/dam/my-site/images/logo.png/.content.xml",
+		"content/my-site/en/.content.xml",
+		"conf/.content.xml",
+		"conf/my-site/settings/wcm/.content.xml",
+	}
+	for _, file := range files {
+		a.Nil(os.MkdirAll(filepath.Join(jcrRootDir, filepath.Dir(file)), 0755))
+		a.Nil(os.WriteFile(filepath.Join(jcrRootDir, file), []byte("<jcr:root/>"), 0644))
+	}
+	a.Nil(os.MkdirAll(filepath.Join(jcrRootDir, "apps/my-app/components"), 0755))
+
+	roots, err := pkg.DeriveFilterRoots(jcrRootDir)
+	a.Nil(err)
+	a.Equal([]string{"/conf/my-site/settings/wcm", "/content/dam/my-site", "/content/my-site/en"}, roots)
+}
+
+func mergeFilter(root string) pkg.Filter {
+	filter := pkg.NewFilter(root)
+	filter.Mode = pkg.FilterModeMerge
+	return filter
+}
@@ -1,8 +1,12 @@
 package pkg
 
 const (
-	MetaPath      = "META-INF"
-	VltDir        = "vault"
-	VltPath       = MetaPath + "/" + VltDir
-	VltProperties = VltPath + "/properties.xml"
+	MetaPath        = "META-INF"
+	VltDir          = "vault"
+	VltPath         = MetaPath + "/" + VltDir
+	VltProperties   = VltPath + "/properties.xml"
+	VltFilter       = VltPath + "/filter.xml"
+	JcrRoot         = "jcr_root"
+	JcrPackagesRoot = JcrRoot + "/etc/packages"
+	ContentXML      = ".content.xml"
 )
@@ -0,0 +1,115 @@
+package pkg
+
+import (
+	"archive/zip"
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/versionx"
+	"sort"
+	"strings"
+)
+
+// Archive represents package file with its dependencies read from properties
+type Archive struct {
+	File         string `json:"file" yaml:"file"`
+	PID          PID    `json:"pid" yaml:"pid"`
+	Dependencies []PID  `json:"dependencies" yaml:"dependencies"`
+}
+
+func ReadArchive(path string) (*Archive, error) {
+	zf, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' cannot be read: %w", path, err)
+	}
+	defer zf.Close()
+	props, err := readPropertiesFromZIP(path, &zf.Reader)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := ParseDependencies(props[PropDependencies])
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' has invalid dependencies: %w", path, err)
+	}
+	return &Archive{File: path, PID: *pidFromProperties(props), Dependencies: dependencies}, nil
+}
+
+func ReadArchives(paths []string) ([]Archive, error) {
+	var result []Archive
+	for _, path := range paths {
+		archive, err := ReadArchive(path)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *archive)
+	}
+	return result, nil
+}
+
+// ParseDependencies reads value of property 'dependencies' (e.g. 'day/cq60/product:cq-content:[6.5.0,7),my-group:my-package:1.0.0')
+func ParseDependencies(str string) ([]PID, error) {
+	var result []PID
+	for _, dependency := range SplitDependencies(str) {
+		pid, err := ParsePID(dependency)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *pid)
+	}
+	return result, nil
+}
+
+// IsSatisfiedBy checks if dependency is fulfilled by the package (version is treated as a range or minimal version)
+func (d PID) IsSatisfiedBy(pid PID) bool {
+	return d.Group == pid.Group && d.Name == pid.Name && versionx.InRange(pid.Version, d.Version)
+}
+
+// LatestArchives keeps only one archive per package group and name (the one having the highest version or, when versions cannot be compared, the one which file path is sorted last like when globbing)
+func LatestArchives(archives []Archive) []Archive {
+	sorted := append([]Archive{}, archives...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].File < sorted[j].File })
+	latest := map[string]Archive{}
+	for _, archive := range sorted {
+		key := archive.PID.Group + ":" + archive.PID.Name
+		current, ok := latest[key]
+		if !ok || !versionx.Less(archive.PID.Version, current.PID.Version) {
+			latest[key] = archive
+		}
+	}
+	return lo.Filter(sorted, func(a Archive, _ int) bool { return latest[a.PID.Group+":"+a.PID.Name].File == a.File })
+}
+
+// SortArchives orders archives so that each one is preceded by the archives it depends on
+func SortArchives(archives []Archive) ([]Archive, error) {
+	var result []Archive
+	remaining := append([]Archive{}, archives...)
+	for len(remaining) > 0 {
+		ready := lo.Filter(remaining, func(a Archive, _ int) bool {
+			return lo.EveryBy(a.Dependencies, func(d PID) bool { return !d.isSatisfiedBySome(remaining) })
+		})
+		if len(ready) == 0 {
+			return nil, fmt.Errorf("packages have cyclic dependencies: %s", strings.Join(lo.Map(remaining, func(a Archive, _ int) string { return a.PID.String() }), ", "))
+		}
+		result = append(result, ready...)
+		remaining = lo.Filter(remaining, func(a Archive, _ int) bool {
+			return !lo.ContainsBy(ready, func(r Archive) bool { return r.File == a.File })
+		})
+	}
+	return result, nil
+}
+
+// ExternalDependencies returns dependencies not fulfilled by any of the given archives
+func ExternalDependencies(archives []Archive) []PID {
+	var result []PID
+	for _, archive := range archives {
+		for _, dependency := range archive.Dependencies {
+			if !dependency.isSatisfiedBySome(archives) && !lo.Contains(result, dependency) {
+				result = append(result, dependency)
+			}
+		}
+	}
+	return result
+}
+
+func (d PID) isSatisfiedBySome(archives []Archive) bool {
+	return lo.SomeBy(archives, func(a Archive) bool { return d.IsSatisfiedBy(a.PID) })
+}
@@ -0,0 +1,38 @@
+package pkg_test
+
+import (
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/pkg"
+	"testing"
+)
+
+func TestSortArchives(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	app := pkg.Archive{File: "app.zip", PID: pkg.PID{Group: "g", Name: "app", Version: "1.0.0"}, Dependencies: []pkg.PID{{Group: "g", Name: "core", Version: "[1.0,2.0)"}}}
+	core := pkg.Archive{File: "core.zip", PID: pkg.PID{Group: "g", Name: "core", Version: "1.1.0"}, Dependencies: []pkg.PID{{Group: "day/cq60/product", Name: "cq-content", Version: "6.5.0"}}}
+
+	sorted, err := pkg.SortArchives([]pkg.Archive{app, core})
+	a.Nil(err)
+	a.Equal([]string{"core.zip", "app.zip"}, []string{sorted[0].File, sorted[1].File})
+	a.Equal([]pkg.PID{{Group: "day/cq60/product", Name: "cq-content", Version: "6.5.0"}}, pkg.ExternalDependencies([]pkg.Archive{app, core}))
+
+	core.Dependencies = []pkg.PID{{Group: "g", Name: "app"}}
+	_, err = pkg.SortArchives([]pkg.Archive{app, core})
+	a.ErrorContains(err, "cyclic")
+}
+
+func TestLatestArchives(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	latest := pkg.LatestArchives([]pkg.Archive{
+		{File: "dist/app-1.10.0.zip", PID: pkg.PID{Group: "g", Name: "app", Version: "1.10.0"}},
+		{File: "dist/app-1.9.0.zip", PID: pkg.PID{Group: "g", Name: "app", Version: "1.9.0"}},
+		{File: "dist/core-1.0.0.zip", PID: pkg.PID{Group: "g", Name: "core", Version: "1.0.0"}},
+		{File: "dist/core-snapshot.zip", PID: pkg.PID{Group: "g", Name: "core", Version: "snapshot"}},
+	})
+	a.Equal([]string{"dist/app-1.10.0.zip", "dist/core-snapshot.zip"}, lo.Map(latest, func(a pkg.Archive, _ int) string { return a.File }))
+}
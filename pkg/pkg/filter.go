@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type FilterDefinition struct {
+	Filters []Filter `json:"filters" yaml:"filters"`
+}
+
+type Filter struct {
+	Root  string       `json:"root" yaml:"root"`
+	Mode  string       `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Rules []FilterRule `json:"rules" yaml:"rules"`
+}
+
+type FilterRule struct {
+	Modifier string `json:"modifier" yaml:"modifier"`
+	Pattern  string `json:"pattern" yaml:"pattern"`
+}
+
+const (
+	FilterRuleInclude = "include"
+	FilterRuleExclude = "exclude"
+)
+
+func NewFilter(root string) Filter {
+	return Filter{Root: root, Rules: []FilterRule{}}
+}
+
+func NewFilters(roots []string) []Filter {
+	return lo.Map(roots, func(root string, _ int) Filter { return NewFilter(root) })
+}
+
+func (f Filter) Validate() error {
+	if !strings.HasPrefix(f.Root, "/") {
+		return fmt.Errorf("package filter root '%s' should be an absolute repository path", f.Root)
+	}
+	for _, rule := range f.Rules {
+		if rule.Modifier != FilterRuleInclude && rule.Modifier != FilterRuleExclude {
+			return fmt.Errorf("package filter root '%s' has rule with unsupported modifier '%s' (expected '%s' or '%s')", f.Root, rule.Modifier, FilterRuleInclude, FilterRuleExclude)
+		}
+	}
+	return nil
+}
+
+// ParseFilterRule reads rule in a format used by package definition node (e.g. 'include:/content/foo(/.*)?')
+func ParseFilterRule(str string) FilterRule {
+	modifier, pattern, ok := strings.Cut(str, ":")
+	if !ok {
+		return FilterRule{Modifier: FilterRuleInclude, Pattern: str}
+	}
+	return FilterRule{Modifier: modifier, Pattern: pattern}
+}
+
+func (r FilterRule) String() string {
+	return fmt.Sprintf("%s:%s", r.Modifier, r.Pattern)
+}
+
+func FiltersEqual(current []Filter, expected []Filter) bool {
+	if len(current) != len(expected) {
+		return false
+	}
+	for i := range current {
+		if current[i].Root != expected[i].Root || current[i].modeNormalized() != expected[i].modeNormalized() {
+			return false
+		}
+		if len(current[i].Rules) != len(expected[i].Rules) {
+			return false
+		}
+		for j := range current[i].Rules {
+			if current[i].Rules[j] != expected[i].Rules[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (f Filter) modeNormalized() string {
+	if f.Mode == "" {
+		return FilterModeReplace
+	}
+	return f.Mode
+}
+
+const (
+	FilterModeReplace = "replace"
+	FilterModeMerge   = "merge"
+	FilterModeUpdate  = "update"
+)
+
+// FiltersFromDefinition reads filters from package definition node (e.g. '/etc/packages/my-group/my-package.zip/jcr:content/vlt:definition/filter')
+func FiltersFromDefinition(node map[string]any) []Filter {
+	names := lo.Filter(lo.Keys(node), func(name string, _ int) bool {
+		_, ok := node[name].(map[string]any)
+		return ok
+	})
+	sort.SliceStable(names, func(i, j int) bool { return filterNodeIndex(names[i]) < filterNodeIndex(names[j]) })
+	return lo.Map(names, func(name string, _ int) Filter {
+		props := node[name].(map[string]any)
+		filter := NewFilter(cast.ToString(props["root"]))
+		filter.Mode = cast.ToString(props["mode"])
+		for _, rule := range cast.ToStringSlice(props["rules"]) {
+			filter.Rules = append(filter.Rules, ParseFilterRule(rule))
+		}
+		return filter
+	})
+}
+
+func filterNodeIndex(name string) int {
+	index, err := strconv.Atoi(strings.TrimPrefix(name, "f"))
+	if err != nil {
+		return math.MaxInt
+	}
+	return index
+}
@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"time"
+)
+
+const (
+	HistoryActionDeploy   = "deploy"
+	HistoryActionRollback = "rollback"
+)
+
+// DeployHistory contains records of packages deployed to a single instance (oldest first)
+type DeployHistory struct {
+	Records []DeployRecord `json:"records" yaml:"records"`
+}
+
+type DeployRecord struct {
+	Action   string    `json:"action" yaml:"action"`
+	Instance string    `json:"instance" yaml:"instance"`
+	PID      string    `json:"pid" yaml:"pid"`
+	File     string    `json:"file,omitempty" yaml:"file,omitempty"`
+	Checksum string    `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	Time     time.Time `json:"time" yaml:"time"`
+}
+
+// ByPackage returns records related to any version of the package (group and name are only compared)
+func (h DeployHistory) ByPackage(pid PID) []DeployRecord {
+	return lo.Filter(h.Records, func(r DeployRecord, _ int) bool {
+		recordPID, err := ParsePID(r.PID)
+		return err == nil && recordPID.Group == pid.Group && recordPID.Name == pid.Name
+	})
+}
+
+// VersionStack replays records of the package and returns PIDs of versions which were installed one after another (oldest first);
+// rolling back removes the versions installed after the one being restored so that repeated rollbacks go further back
+func (h DeployHistory) VersionStack(pid PID) []string {
+	var stack []string
+	for _, record := range h.ByPackage(pid) {
+		switch record.Action {
+		case HistoryActionDeploy:
+			if len(stack) == 0 || stack[len(stack)-1] != record.PID {
+				stack = append(stack, record.PID)
+			}
+		case HistoryActionRollback:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			for len(stack) > 0 && stack[len(stack)-1] != record.PID {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				stack = append(stack, record.PID)
+			}
+		}
+	}
+	return stack
+}
+
+func (h DeployHistory) MarshalText() string {
+	return fmtx.TblRows("history", true, []string{"time", "action", "pid", "checksum", "file"}, lo.Map(h.Records, func(r DeployRecord, _ int) map[string]any {
+		return map[string]any{
+			"time":     r.Time,
+			"action":   r.Action,
+			"pid":      r.PID,
+			"checksum": r.Checksum,
+			"file":     r.File,
+		}
+	}))
+}
@@ -0,0 +1,31 @@
+package pkg_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/pkg"
+	"testing"
+)
+
+func TestDeployHistoryVersionStack(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	pid := pkg.PID{Group: "my-group", Name: "my-app"}
+	history := pkg.DeployHistory{Records: []pkg.DeployRecord{
+		{Action: pkg.HistoryActionDeploy, PID: "my-group:my-app:1.0.0"},
+		{Action: pkg.HistoryActionDeploy, PID: "my-group:other:1.0.0"},
+		{Action: pkg.HistoryActionDeploy, PID: "my-group:my-app:2.0.0"},
+		{Action: pkg.HistoryActionDeploy, PID: "my-group:my-app:3.0.0"},
+		{Action: pkg.HistoryActionDeploy, PID: "my-group:my-app:3.0.0"},
+	}}
+	a.Equal([]string{"my-group:my-app:1.0.0", "my-group:my-app:2.0.0", "my-group:my-app:3.0.0"}, history.VersionStack(pid))
+
+	history.Records = append(history.Records, pkg.DeployRecord{Action: pkg.HistoryActionRollback, PID: "my-group:my-app:2.0.0"})
+	a.Equal([]string{"my-group:my-app:1.0.0", "my-group:my-app:2.0.0"}, history.VersionStack(pid))
+
+	history.Records = append(history.Records, pkg.DeployRecord{Action: pkg.HistoryActionRollback, PID: "my-group:my-app:1.0.0"})
+	a.Equal([]string{"my-group:my-app:1.0.0"}, history.VersionStack(pid))
+
+	history.Records = append(history.Records, pkg.DeployRecord{Action: pkg.HistoryActionDeploy, PID: "my-group:my-app:4.0.0"})
+	a.Equal([]string{"my-group:my-app:1.0.0", "my-group:my-app:4.0.0"}, history.VersionStack(pid))
+}
@@ -0,0 +1,270 @@
+package pkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"github.com/antchfx/xmlquery"
+	"github.com/dustin/go-humanize"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"github.com/wttech/aemc/pkg/osgi"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Inspection describes package contents read from ZIP file without communicating with AEM instance
+type Inspection struct {
+	File               string            `json:"file" yaml:"file"`
+	PID                PID               `json:"pid" yaml:"pid"`
+	Description        string            `json:"description" yaml:"description"`
+	CreatedBy          string            `json:"createdBy" yaml:"created_by"`
+	ACHandling         string            `json:"acHandling" yaml:"ac_handling"`
+	SubPackageHandling string            `json:"subPackageHandling" yaml:"sub_package_handling"`
+	Dependencies       []string          `json:"dependencies" yaml:"dependencies"`
+	Properties         map[string]string `json:"properties" yaml:"properties"`
+	Filters            []Filter          `json:"filters" yaml:"filters"`
+	Bundles            []EmbeddedBundle  `json:"bundles" yaml:"bundles"`
+	SubPackages        []EmbeddedPackage `json:"subPackages" yaml:"sub_packages"`
+	FileSize           int64             `json:"fileSize" yaml:"file_size"`
+	ContentSize        int64             `json:"contentSize" yaml:"content_size"`
+	ContentFiles       int               `json:"contentFiles" yaml:"content_files"`
+	Warnings           []string          `json:"warnings" yaml:"warnings"`
+}
+
+type EmbeddedBundle struct {
+	Path         string `json:"path" yaml:"path"`
+	SymbolicName string `json:"symbolicName" yaml:"symbolic_name"`
+	Version      string `json:"version" yaml:"version"`
+	Size         int64  `json:"size" yaml:"size"`
+}
+
+type EmbeddedPackage struct {
+	Path string `json:"path" yaml:"path"`
+	PID  PID    `json:"pid" yaml:"pid"`
+	Size int64  `json:"size" yaml:"size"`
+}
+
+func Inspect(localPath string) (*Inspection, error) {
+	stat, err := os.Stat(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' cannot be inspected: %w", localPath, err)
+	}
+	zf, err := zip.OpenReader(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' cannot be read: %w", localPath, err)
+	}
+	defer zf.Close()
+	props, err := readPropertiesFromZIP(localPath, &zf.Reader)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := readFiltersFromZIP(localPath, &zf.Reader)
+	if err != nil {
+		return nil, err
+	}
+	result := &Inspection{
+		File:               localPath,
+		PID:                *pidFromProperties(props),
+		Description:        props[PropDescription],
+		CreatedBy:          props[PropCreatedBy],
+		ACHandling:         props[PropACHandling],
+		SubPackageHandling: props[PropSubPackages],
+		Dependencies:       SplitDependencies(props[PropDependencies]),
+		Properties:         props,
+		Filters:            filters,
+		Bundles:            []EmbeddedBundle{},
+		SubPackages:        []EmbeddedPackage{},
+		FileSize:           stat.Size(),
+		Warnings:           []string{},
+	}
+	for _, entry := range zf.File {
+		if entry.FileInfo().IsDir() || !strings.HasPrefix(entry.Name, JcrRoot+"/") {
+			continue
+		}
+		result.ContentFiles++
+		result.ContentSize += int64(entry.UncompressedSize64)
+		ext := path.Ext(entry.Name)
+		if ext == ".jar" && isInstallPath(entry.Name) {
+			bundle, err := readEmbeddedBundle(localPath, entry)
+			if err != nil {
+				result.Warnings = append(result.Warnings, err.Error())
+				continue
+			}
+			result.Bundles = append(result.Bundles, *bundle)
+		} else if ext == ".zip" && (isInstallPath(entry.Name) || strings.HasPrefix(entry.Name, JcrPackagesRoot+"/")) {
+			subPackage, err := readEmbeddedPackage(localPath, entry)
+			if err != nil {
+				result.Warnings = append(result.Warnings, err.Error()) // e.g. plain ZIP not being a package
+				continue
+			}
+			result.SubPackages = append(result.SubPackages, *subPackage)
+		}
+	}
+	return result, nil
+}
+
+// isInstallPath checks if file is located in one of dirs scanned by Sling OSGi Installer (e.g. '/apps/my-app/install.author')
+func isInstallPath(entryPath string) bool {
+	parts := strings.Split(entryPath, "/")
+	if len(parts) < 4 || parts[1] != "apps" {
+		return false
+	}
+	return lo.SomeBy(parts[2:len(parts)-1], func(dir string) bool {
+		return dir == "install" || strings.HasPrefix(dir, "install.")
+	})
+}
+
+func readEmbeddedBundle(localPath string, entry *zip.File) (*EmbeddedBundle, error) {
+	zr, err := openEmbeddedZIP(localPath, entry)
+	if err != nil {
+		return nil, err
+	}
+	result := &EmbeddedBundle{Path: entryRepoPath(entry), Size: int64(entry.UncompressedSize64)}
+	manifest, err := zr.Open(osgi.ManifestPath)
+	if err != nil {
+		return result, nil // not an OSGi bundle, just a plain JAR
+	}
+	defer manifest.Close()
+	bundleManifest, err := osgi.ParseBundleManifest(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' has embedded bundle '%s' with invalid manifest: %w", localPath, entry.Name, err)
+	}
+	result.SymbolicName = bundleManifest.SymbolicName
+	result.Version = bundleManifest.Version
+	return result, nil
+}
+
+func readEmbeddedPackage(localPath string, entry *zip.File) (*EmbeddedPackage, error) {
+	zr, err := openEmbeddedZIP(localPath, entry)
+	if err != nil {
+		return nil, err
+	}
+	entryPath := fmt.Sprintf("%s!/%s", localPath, entry.Name)
+	props, err := readPropertiesFromZIP(entryPath, zr)
+	if err != nil {
+		return nil, err
+	}
+	return &EmbeddedPackage{Path: entryRepoPath(entry), PID: *pidFromProperties(props), Size: int64(entry.UncompressedSize64)}, nil
+}
+
+func openEmbeddedZIP(localPath string, entry *zip.File) (*zip.Reader, error) {
+	fh, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' has embedded file '%s' that cannot be opened: %w", localPath, entry.Name, err)
+	}
+	defer fh.Close()
+	data, err := io.ReadAll(fh)
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' has embedded file '%s' that cannot be read: %w", localPath, entry.Name, err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' has embedded file '%s' that is not a valid archive: %w", localPath, entry.Name, err)
+	}
+	return zr, nil
+}
+
+func entryRepoPath(entry *zip.File) string {
+	return strings.TrimPrefix(entry.Name, JcrRoot)
+}
+
+func readFiltersFromZIP(localPath string, zr *zip.Reader) ([]Filter, error) {
+	entry, err := zr.Open(VltFilter)
+	if err != nil {
+		return []Filter{}, nil
+	}
+	defer entry.Close()
+	doc, err := xmlquery.Parse(entry)
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' has filter file '%s' that cannot be parsed: %w", localPath, VltFilter, err)
+	}
+	return lo.Map(xmlquery.Find(doc, "//filter"), func(node *xmlquery.Node, _ int) Filter {
+		filter := NewFilter(node.SelectAttr("root"))
+		filter.Mode = node.SelectAttr("mode")
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == xmlquery.ElementNode && (child.Data == FilterRuleInclude || child.Data == FilterRuleExclude) {
+				filter.Rules = append(filter.Rules, FilterRule{Modifier: child.Data, Pattern: child.SelectAttr("pattern")})
+			}
+		}
+		return filter
+	}), nil
+}
+
+// SplitDependencies reads comma-separated dependencies respecting commas used in version ranges (e.g. 'my-group:my-package:[1.0,2.0)')
+func SplitDependencies(str string) []string {
+	result := []string{}
+	depth := 0
+	current := strings.Builder{}
+	for _, char := range str {
+		switch char {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = appendDependency(result, current.String())
+				current.Reset()
+				continue
+			}
+		}
+		current.WriteRune(char)
+	}
+	return appendDependency(result, current.String())
+}
+
+func appendDependency(dependencies []string, dependency string) []string {
+	dependency = strings.TrimSpace(dependency)
+	if dependency == "" {
+		return dependencies
+	}
+	return append(dependencies, dependency)
+}
+
+func (i Inspection) MarshalText() string {
+	bs := bytes.NewBufferString("")
+	bs.WriteString(fmt.Sprintf("PID '%s'\n", i.PID.String()))
+	bs.WriteString(fmtx.TblMap("details", "name", "value", map[string]any{
+		"file":          i.File,
+		"description":   i.Description,
+		"created by":    i.CreatedBy,
+		"ac handling":   i.ACHandling,
+		"dependencies":  i.Dependencies,
+		"sub packages":  i.SubPackageHandling,
+		"file size":     humanize.Bytes(uint64(i.FileSize)),
+		"content size":  humanize.Bytes(uint64(i.ContentSize)),
+		"content files": i.ContentFiles,
+	}))
+	bs.WriteString(fmtx.TblRows("filters", true, []string{"root", "mode", "rules"}, lo.Map(i.Filters, func(f Filter, _ int) map[string]any {
+		return map[string]any{
+			"root":  f.Root,
+			"mode":  f.modeNormalized(),
+			"rules": lo.Map(f.Rules, func(r FilterRule, _ int) string { return r.String() }),
+		}
+	})))
+	bs.WriteString(fmtx.TblRows("bundles", true, []string{"symbolic name", "version", "size", "path"}, lo.Map(i.Bundles, func(b EmbeddedBundle, _ int) map[string]any {
+		return map[string]any{
+			"symbolic name": b.SymbolicName,
+			"version":       b.Version,
+			"size":          humanize.Bytes(uint64(b.Size)),
+			"path":          b.Path,
+		}
+	})))
+	bs.WriteString(fmtx.TblRows("sub packages", true, []string{"pid", "size", "path"}, lo.Map(i.SubPackages, func(p EmbeddedPackage, _ int) map[string]any {
+		return map[string]any{
+			"pid":  p.PID.String(),
+			"size": humanize.Bytes(uint64(p.Size)),
+			"path": p.Path,
+		}
+	})))
+	if len(i.Warnings) > 0 {
+		bs.WriteString(fmtx.TblRows("warnings", true, []string{"message"}, lo.Map(i.Warnings, func(w string, _ int) map[string]any {
+			return map[string]any{"message": w}
+		})))
+	}
+	bs.WriteString(fmtx.TblProps(lo.MapValues(i.Properties, func(v string, _ string) any { return v })))
+	return bs.String()
+}
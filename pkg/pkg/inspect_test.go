@@ -0,0 +1,109 @@
+package pkg_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/pkg"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	file := filepath.Join(t.TempDir(), "example.zip")
+	a.Nil(os.WriteFile(file, zipBytes(t, map[string][]byte{
+		pkg.VltProperties: propertiesXML(map[string]string{
+			"group":              "my-group",
+			"name":               "my-package",
+			"version":            "1.0.0",
+			"description":        "Example package",
+			"acHandling":         "merge_preserve",
+			"dependencies":       "day/cq60/product:cq-content:[6.5.0,7),my-group:my-base:1.0.0",
+			"subPackageHandling": "my-group:my-sub;install",
+		}),
+		pkg.VltFilter: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<workspaceFilter version="1.0">
+    <filter root="/apps/my-app" mode="merge">
+        <include pattern="/apps/my-app/components(/.*)?"/>
+        <exclude pattern="/apps/my-app/install(/.*)?"/>
+    </filter>
+    <filter root="/conf/my-app"/>
+</workspaceFilter>`),
+		"jcr_root/apps/my-app/install.author/my-bundle.jar": zipBytes(t, map[string][]byte{
+			"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\nBundle-SymbolicName: com.example.my-bundle;singleton:=true\nBundle-Version: 2.1.0\n"),
+		}),
+		"jcr_root/etc/packages/my-group/my-sub.zip": zipBytes(t, map[string][]byte{
+			pkg.VltProperties: propertiesXML(map[string]string{"group": "my-group", "name": "my-sub", "version": "3.0.0"}),
+		}),
+		"jcr_root/apps/my-app/install/plain.zip": zipBytes(t, map[string][]byte{
+			"readme.txt": []byte("not a package"),
+		}),
+		"jcr_root/conf/my-app/.content.xml": []byte("<jcr:root/>"),
+	}), 0644))
+
+	inspection, err := pkg.Inspect(file)
+	a.Nil(err)
+
+	a.Equal("my-group:my-package:1.0.0", inspection.PID.String())
+	a.Equal("Example package", inspection.Description)
+	a.Equal("merge_preserve", inspection.ACHandling)
+	a.Equal([]string{"day/cq60/product:cq-content:[6.5.0,7)", "my-group:my-base:1.0.0"}, inspection.Dependencies)
+
+	a.Len(inspection.Filters, 2)
+	a.Equal("/apps/my-app", inspection.Filters[0].Root)
+	a.Equal("merge", inspection.Filters[0].Mode)
+	a.Equal([]pkg.FilterRule{
+		{Modifier: pkg.FilterRuleInclude, Pattern: "/apps/my-app/components(/.*)?"},
+		{Modifier: pkg.FilterRuleExclude, Pattern: "/apps/my-app/install(/.*)?"},
+	}, inspection.Filters[0].Rules)
+	a.Empty(inspection.Filters[1].Rules)
+
+	a.Len(inspection.Bundles, 1)
+	a.Equal("com.example.my-bundle", inspection.Bundles[0].SymbolicName)
+	a.Equal("2.1.0", inspection.Bundles[0].Version)
+	a.Equal("/apps/my-app/install.author/my-bundle.jar", inspection.Bundles[0].Path)
+
+	a.Len(inspection.SubPackages, 1)
+	a.Equal("my-group:my-sub:3.0.0", inspection.SubPackages[0].PID.String())
+
+	a.Equal("my-group:my-sub;install", inspection.SubPackageHandling)
+
+	a.Len(inspection.Warnings, 1)
+	a.Contains(inspection.Warnings[0], "jcr_root/apps/my-app/install/plain.zip")
+
+	a.Equal(4, inspection.ContentFiles)
+}
+
+func zipBytes(t *testing.T, files map[string][]byte) []byte {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func propertiesXML(props map[string]string) []byte {
+	buf := bytes.NewBufferString(`<?xml version="1.0" encoding="utf-8" standalone="no"?>
+<!DOCTYPE properties SYSTEM "http://java.sun.com/dtd/properties.dtd">
+<properties>
+`)
+	for k, v := range props {
+		buf.WriteString(`<entry key="` + k + `">` + v + "</entry>\n")
+	}
+	buf.WriteString("</properties>\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"html"
+	"regexp"
+	"strings"
+)
+
+const (
+	InstallActionAdded   = "A"
+	InstallActionUpdated = "U"
+	InstallActionDeleted = "D"
+	InstallActionError   = "E"
+)
+
+const (
+	ACHandlingIgnore        = "ignore"
+	ACHandlingOverwrite     = "overwrite"
+	ACHandlingMerge         = "merge"
+	ACHandlingMergePreserve = "merge_preserve"
+	ACHandlingClear         = "clear"
+)
+
+func ACHandlings() []string {
+	return []string{ACHandlingIgnore, ACHandlingOverwrite, ACHandlingMerge, ACHandlingMergePreserve, ACHandlingClear}
+}
+
+const (
+	DependencyHandlingStrict     = "strict"
+	DependencyHandlingRequired   = "required"
+	DependencyHandlingBestEffort = "best_effort"
+)
+
+func DependencyHandlings() []string {
+	return []string{DependencyHandlingStrict, DependencyHandlingRequired, DependencyHandlingBestEffort}
+}
+
+// InstallLog is a parsed output of package installation returned by HTML service of package manager
+type InstallLog struct {
+	Actions []InstallAction `json:"actions" yaml:"actions"`
+	Failure string          `json:"failure,omitempty" yaml:"failure,omitempty"`
+}
+
+type InstallAction struct {
+	Type    string `json:"type" yaml:"type"`
+	Path    string `json:"path" yaml:"path"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+var (
+	installActionRegex  = regexp.MustCompile(`<span class="([A-Z])"><b>[A-Z]</b>&nbsp;(.*?)</span>`)
+	installFailureTexts = []string{"Package installation failed", "Error during processing", "Package imported (with errors"}
+)
+
+// ParseInstallLog reads HTML response of command 'install' (e.g. '<span class="E"><b>E</b>&nbsp;/apps/foo (javax.jcr.nodetype.ConstraintViolationException: ...)</span>')
+func ParseInstallLog(text string) InstallLog {
+	result := InstallLog{Actions: []InstallAction{}}
+	for _, match := range installActionRegex.FindAllStringSubmatch(text, -1) {
+		content := strings.TrimSpace(html.UnescapeString(match[2]))
+		action := InstallAction{Type: match[1], Path: content}
+		if path, message, ok := strings.Cut(content, " ("); ok && strings.HasSuffix(message, ")") {
+			action.Path = path
+			action.Message = strings.TrimSuffix(message, ")")
+		}
+		result.Actions = append(result.Actions, action)
+	}
+	failure, ok := lo.Find(installFailureTexts, func(t string) bool { return strings.Contains(text, t) })
+	if ok {
+		result.Failure = failure
+	}
+	return result
+}
+
+func (l InstallLog) Errors() []InstallAction {
+	return l.ActionsOfType(InstallActionError)
+}
+
+func (l InstallLog) ActionsOfType(actionType string) []InstallAction {
+	return lo.Filter(l.Actions, func(a InstallAction, _ int) bool { return a.Type == actionType })
+}
+
+func (l InstallLog) Failed() bool {
+	return l.Failure != "" || len(l.Errors()) > 0
+}
+
+func (l InstallLog) MarshalText() string {
+	bs := bytes.NewBufferString("")
+	bs.WriteString(fmtx.TblMap("summary", "action", "count", map[string]any{
+		"added":   len(l.ActionsOfType(InstallActionAdded)),
+		"updated": len(l.ActionsOfType(InstallActionUpdated)),
+		"deleted": len(l.ActionsOfType(InstallActionDeleted)),
+		"errors":  len(l.Errors()),
+	}))
+	bs.WriteString(fmtx.TblRows("errors", true, []string{"path", "message"}, lo.Map(l.Errors(), func(a InstallAction, _ int) map[string]any {
+		return map[string]any{"path": a.Path, "message": a.Message}
+	})))
+	return bs.String()
+}
+
+// InstallError is returned when package installation log contains failures
+type InstallError struct {
+	InstanceID string
+	RemotePath string
+	Log        InstallLog
+}
+
+func (e InstallError) FailedPaths() []string {
+	return lo.Map(e.Log.Errors(), func(a InstallAction, _ int) string { return a.Path })
+}
+
+func (e InstallError) Error() string {
+	details := lo.Map(e.Log.Errors(), func(a InstallAction, _ int) string {
+		if a.Message == "" {
+			return a.Path
+		}
+		return fmt.Sprintf("%s (%s)", a.Path, a.Message)
+	})
+	if len(details) == 0 {
+		return fmt.Sprintf("%s > cannot install package '%s': %s", e.InstanceID, e.RemotePath, e.Log.Failure)
+	}
+	return fmt.Sprintf("%s > cannot install package '%s'; failed paths: %s", e.InstanceID, e.RemotePath, strings.Join(details, ", "))
+}
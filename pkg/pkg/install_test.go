@@ -0,0 +1,28 @@
+package pkg_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/pkg"
+	"testing"
+)
+
+func TestParseInstallLog(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	installLog := pkg.ParseInstallLog(`<span class="A"><b>A</b>&nbsp;/apps/my-app (nt:folder)</span><br>
+<span class="U"><b>U</b>&nbsp;/apps/my-app/components</span><br>
+<span class="E"><b>E</b>&nbsp;/apps/my-app/config (javax.jcr.nodetype.ConstraintViolationException: no matching property definition)</span><br>
+Package imported (with errors, check logs!)<br>`)
+
+	a.Len(installLog.Actions, 3)
+	a.Equal(pkg.InstallAction{Type: pkg.InstallActionAdded, Path: "/apps/my-app", Message: "nt:folder"}, installLog.Actions[0])
+	a.Equal("/apps/my-app/components", installLog.Actions[1].Path)
+	a.True(installLog.Failed())
+
+	err := pkg.InstallError{InstanceID: "local_author", RemotePath: "/etc/packages/my-group/my-app.zip", Log: installLog}
+	a.Equal([]string{"/apps/my-app/config"}, err.FailedPaths())
+	a.Contains(err.Error(), "failed paths: /apps/my-app/config (javax.jcr.nodetype.ConstraintViolationException")
+
+	a.False(pkg.ParseInstallLog(`<span class="D"><b>D</b>&nbsp;/apps/old</span><br>Package imported.`).Failed())
+}
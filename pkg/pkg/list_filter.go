@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/stringsx"
+	"sort"
+	"time"
+)
+
+const (
+	ListSortSize         = "size"
+	ListSortLastUnpacked = "lastUnpacked"
+	ListSortName         = "name"
+)
+
+func ListSorts() []string {
+	return []string{ListSortSize, ListSortLastUnpacked, ListSortName}
+}
+
+// ListFilter narrows down package list (empty values mean no filtering)
+type ListFilter struct {
+	Group         string
+	Name          string // glob pattern
+	Installed     bool
+	Built         bool
+	ModifiedAfter time.Time
+	Sort          string
+	Limit         int
+}
+
+func (f ListFilter) Validate() error {
+	if f.Sort != "" && !lo.Contains(ListSorts(), f.Sort) {
+		return fmt.Errorf("package list sort '%s' is not supported (expected one of: %v)", f.Sort, ListSorts())
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("package list limit '%d' cannot be negative", f.Limit)
+	}
+	return nil
+}
+
+func (f ListFilter) Apply(items []ListItem) []ListItem {
+	result := lo.Filter(items, func(i ListItem, _ int) bool { return f.matches(i) })
+	switch f.Sort {
+	case ListSortSize:
+		sort.SliceStable(result, func(i, j int) bool { return result[i].Size > result[j].Size })
+	case ListSortLastUnpacked:
+		sort.SliceStable(result, func(i, j int) bool { return result[i].LastUnpacked > result[j].LastUnpacked })
+	case ListSortName:
+		sort.SliceStable(result, func(i, j int) bool {
+			if result[i].Name == result[j].Name {
+				return result[i].Version < result[j].Version
+			}
+			return result[i].Name < result[j].Name
+		})
+	}
+	if f.Limit > 0 && len(result) > f.Limit {
+		result = result[:f.Limit]
+	}
+	return result
+}
+
+func (f ListFilter) matches(item ListItem) bool {
+	if f.Group != "" && item.Group != f.Group {
+		return false
+	}
+	if f.Name != "" && !stringsx.Match(item.Name, f.Name) {
+		return false
+	}
+	if f.Installed && !item.Installed() {
+		return false
+	}
+	if f.Built && !item.Built() {
+		return false
+	}
+	if !f.ModifiedAfter.IsZero() && time.UnixMilli(int64(item.LastModified)).Before(f.ModifiedAfter) {
+		return false
+	}
+	return true
+}
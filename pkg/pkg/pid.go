@@ -3,7 +3,6 @@ package pkg
 import (
 	"archive/zip"
 	"fmt"
-	"github.com/antchfx/xmlquery"
 	"github.com/samber/lo"
 	"strings"
 )
@@ -36,27 +35,13 @@ func ReadPIDFromZIP(path string) (*PID, error) {
 		return nil, fmt.Errorf("package '%s' cannot be read: %w", path, err)
 	}
 	defer zf.Close()
-	for _, entry := range zf.File {
-		if entry.Name == VltProperties {
-			return readPIDFromZipEntry(path, entry)
-		}
+	props, err := readPropertiesFromZIP(path, &zf.Reader)
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("package '%s' has no properties file '%s' required to determine PID", path, VltProperties)
+	return pidFromProperties(props), nil
 }
 
-func readPIDFromZipEntry(path string, file *zip.File) (*PID, error) {
-	fh, err := file.Open()
-	if err != nil {
-		return nil, fmt.Errorf("package '%s' has properties file '%s' that cannot be read: %w", path, VltProperties, err)
-	}
-	defer fh.Close()
-	doc, err := xmlquery.Parse(fh)
-	if err != nil {
-		return nil, fmt.Errorf("package '%s' has properties file '%s' that cannot be parsed: %w", path, VltProperties, err)
-	}
-	return &PID{
-		doc.SelectElement("//entry[@key=\"group\"]").InnerText(),
-		doc.SelectElement("//entry[@key=\"name\"]").InnerText(),
-		doc.SelectElement("//entry[@key=\"version\"]").InnerText(),
-	}, nil
+func pidFromProperties(props map[string]string) *PID {
+	return &PID{props[PropGroup], props[PropName], props[PropVersion]}
 }
@@ -0,0 +1,35 @@
+package pkg
+
+import (
+	"archive/zip"
+	"fmt"
+	"github.com/antchfx/xmlquery"
+)
+
+const (
+	PropGroup        = "group"
+	PropName         = "name"
+	PropVersion      = "version"
+	PropDescription  = "description"
+	PropCreatedBy    = "createdBy"
+	PropACHandling   = "acHandling"
+	PropDependencies = "dependencies"
+	PropSubPackages  = "subPackageHandling"
+)
+
+func readPropertiesFromZIP(path string, zr *zip.Reader) (map[string]string, error) {
+	entry, err := zr.Open(VltProperties)
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' has no properties file '%s' required to determine PID", path, VltProperties)
+	}
+	defer entry.Close()
+	doc, err := xmlquery.Parse(entry)
+	if err != nil {
+		return nil, fmt.Errorf("package '%s' has properties file '%s' that cannot be parsed: %w", path, VltProperties, err)
+	}
+	result := map[string]string{}
+	for _, node := range xmlquery.Find(doc, "//entry") {
+		result[node.SelectAttr("key")] = node.InnerText()
+	}
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/dustin/go-humanize"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"github.com/wttech/aemc/pkg/common/intsx"
+	"sort"
+	"time"
+)
+
+// PurgePolicy decides which packages could be safely deleted (currently installed versions are always kept)
+type PurgePolicy struct {
+	KeepVersions  int
+	OlderThan     time.Duration
+	Group         string
+	SnapshotsOnly bool
+}
+
+func (p PurgePolicy) Validate() error {
+	if p.KeepVersions < 0 {
+		return fmt.Errorf("package purge policy cannot keep negative number of versions '%d'", p.KeepVersions)
+	}
+	if p.KeepVersions == 0 && p.OlderThan <= 0 {
+		return fmt.Errorf("package purge policy requires versions to keep or age of packages to be specified")
+	}
+	return nil
+}
+
+// Candidates returns packages to be purged; snapshot checker is used only when purging snapshots only
+func (p PurgePolicy) Candidates(items []ListItem, snapshot func(ListItem) bool, now time.Time) []ListItem {
+	var result []ListItem
+	items = lo.Filter(items, func(i ListItem, _ int) bool {
+		return (p.Group == "" || i.Group == p.Group) && (!p.SnapshotsOnly || snapshot(i))
+	})
+	groups := lo.GroupBy(items, func(i ListItem) string { return i.Group + ":" + i.Name })
+	keys := lo.Keys(groups)
+	sort.Strings(keys)
+	for _, key := range keys {
+		versions := groups[key]
+		sort.SliceStable(versions, func(i, j int) bool { return lastUsed(versions[i]) > lastUsed(versions[j]) })
+		installed := lo.Filter(versions, func(i ListItem, _ int) bool { return i.Installed() })
+		current := ""
+		if len(installed) > 0 {
+			current = lo.MaxBy(installed, func(a ListItem, b ListItem) bool { return a.LastUnpacked > b.LastUnpacked }).PID
+		}
+		for index, version := range versions {
+			if version.PID == current || index < p.KeepVersions {
+				continue
+			}
+			if p.OlderThan > 0 && now.Sub(time.UnixMilli(int64(lastUsed(version)))) < p.OlderThan {
+				continue
+			}
+			result = append(result, version)
+		}
+	}
+	return result
+}
+
+func lastUsed(item ListItem) int {
+	return intsx.MaxOf(item.LastTouched(), item.LastUnpacked)
+}
+
+type PurgeResult struct {
+	DryRun         bool       `json:"dryRun" yaml:"dry_run"`
+	Packages       []ListItem `json:"packages" yaml:"packages"`
+	ReclaimedBytes int64      `json:"reclaimedBytes" yaml:"reclaimed_bytes"`
+}
+
+func NewPurgeResult(items []ListItem, dryRun bool) *PurgeResult {
+	return &PurgeResult{
+		DryRun:         dryRun,
+		Packages:       items,
+		ReclaimedBytes: lo.SumBy(items, func(i ListItem) int64 { return int64(i.Size) }),
+	}
+}
+
+func (r PurgeResult) MarshalText() string {
+	bs := bytes.NewBufferString("")
+	bs.WriteString(fmtx.TblMap("summary", "name", "value", map[string]any{
+		"dry run":   r.DryRun,
+		"packages":  len(r.Packages),
+		"reclaimed": humanize.Bytes(uint64(r.ReclaimedBytes)),
+	}))
+	bs.WriteString(fmtx.TblRows("packages", true, []string{"pid", "size", "installed", "modified"}, lo.Map(r.Packages, func(i ListItem, _ int) map[string]any {
+		return map[string]any{
+			"pid":       i.PID,
+			"size":      humanize.Bytes(uint64(i.Size)),
+			"installed": listItemDate(i.LastUnpacked),
+			"modified":  listItemDate(i.LastModified),
+		}
+	})))
+	return bs.String()
+}
@@ -0,0 +1,37 @@
+package pkg_test
+
+import (
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/pkg"
+	"testing"
+	"time"
+)
+
+func TestPurgePolicyCandidates(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	now := time.Now()
+	daysAgo := func(days int) int { return int(now.Add(-time.Duration(days) * 24 * time.Hour).UnixMilli()) }
+	items := []pkg.ListItem{
+		{PID: "g:app:1.0.0", Group: "g", Name: "app", Size: 100, Created: daysAgo(90)},
+		{PID: "g:app:2.0.0", Group: "g", Name: "app", Size: 200, Created: daysAgo(60), LastUnpacked: daysAgo(60)},
+		{PID: "g:app:3.0.0", Group: "g", Name: "app", Size: 300, Created: daysAgo(40)},
+		{PID: "g:app:4.0.0-SNAPSHOT", Group: "g", Name: "app", Size: 400, Created: daysAgo(1)},
+		{PID: "other:lib:1.0.0", Group: "other", Name: "lib", Size: 500, Created: daysAgo(100)},
+	}
+	notSnapshot := func(pkg.ListItem) bool { return false }
+	pids := func(items []pkg.ListItem) []string {
+		return lo.Map(items, func(i pkg.ListItem, _ int) string { return i.PID })
+	}
+
+	a.Equal([]string{"g:app:1.0.0"}, pids(pkg.PurgePolicy{KeepVersions: 2, Group: "g"}.Candidates(items, notSnapshot, now)))
+	a.Equal([]string{"g:app:3.0.0", "g:app:1.0.0", "other:lib:1.0.0"}, pids(pkg.PurgePolicy{OlderThan: 30 * 24 * time.Hour}.Candidates(items, notSnapshot, now)))
+
+	result := pkg.NewPurgeResult(pkg.PurgePolicy{KeepVersions: 1}.Candidates(items, notSnapshot, now), true)
+	a.Equal([]string{"g:app:3.0.0", "g:app:1.0.0"}, pids(result.Packages))
+	a.Equal(int64(400), result.ReclaimedBytes)
+
+	a.NotNil(pkg.PurgePolicy{Group: "g"}.Validate())
+}
@@ -0,0 +1,77 @@
+package repl
+
+import (
+	"bytes"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+)
+
+// Queue is a response of replication agent queue servlet (e.g. '/etc/replication/agents.author/publish/jcr:content.queue.json')
+type Queue struct {
+	MetaData QueueMetaData `json:"metaData" yaml:"meta_data"`
+	Items    []QueueItem   `json:"queue" yaml:"items"`
+}
+
+type QueueMetaData struct {
+	Status QueueStatus `json:"queueStatus" yaml:"status"`
+}
+
+type QueueStatus struct {
+	AgentName       string `json:"agentName" yaml:"agent_name"`
+	AgentID         string `json:"agentId" yaml:"agent_id"`
+	Blocked         bool   `json:"isBlocked" yaml:"blocked"`
+	Paused          bool   `json:"isPaused" yaml:"paused"`
+	NextRetryPeriod int64  `json:"nextRetryPeriod" yaml:"next_retry_period"`
+	ProcessingSince int64  `json:"processingSince" yaml:"processing_since"`
+}
+
+type QueueItem struct {
+	ID         string `json:"id" yaml:"id"`
+	Path       string `json:"path" yaml:"path"`
+	Type       string `json:"type" yaml:"type"`
+	Time       int64  `json:"time" yaml:"time"`
+	UserID     string `json:"userid" yaml:"user_id"`
+	LastError  string `json:"lastProcessingError,omitempty" yaml:"last_error,omitempty"`
+	NumRetries int    `json:"numProcessed" yaml:"num_retries"`
+}
+
+func (q Queue) Empty() bool {
+	return len(q.Items) == 0
+}
+
+// BlockedItems returns items which failed to be replicated at least once
+func (q Queue) BlockedItems() []QueueItem {
+	return lo.Filter(q.Items, func(i QueueItem, _ int) bool { return i.LastError != "" })
+}
+
+// LastError returns error of first blocked item as queue is processed in order
+func (q Queue) LastError() string {
+	blocked := q.BlockedItems()
+	if len(blocked) == 0 {
+		return ""
+	}
+	return blocked[0].LastError
+}
+
+func (q Queue) MarshalText() string {
+	bs := bytes.NewBufferString("")
+	bs.WriteString(fmtx.TblMap("status", "name", "value", map[string]any{
+		"agent":         q.MetaData.Status.AgentName,
+		"blocked":       q.MetaData.Status.Blocked,
+		"paused":        q.MetaData.Status.Paused,
+		"size":          len(q.Items),
+		"blocked items": len(q.BlockedItems()),
+		"last error":    q.LastError(),
+	}))
+	bs.WriteString(fmtx.TblRows("items", true, []string{"id", "type", "path", "user", "retries", "error"}, lo.Map(q.Items, func(i QueueItem, _ int) map[string]any {
+		return map[string]any{
+			"id":      i.ID,
+			"type":    i.Type,
+			"path":    i.Path,
+			"user":    i.UserID,
+			"retries": i.NumRetries,
+			"error":   i.LastError,
+		}
+	})))
+	return bs.String()
+}
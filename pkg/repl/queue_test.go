@@ -0,0 +1,27 @@
+package repl_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/repl"
+	"testing"
+)
+
+func TestQueueBlockedItems(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var queue repl.Queue
+	a.NoError(json.Unmarshal([]byte(`{
+		"metaData": {"queueStatus": {"agentName": "publish", "isBlocked": true, "isPaused": false}},
+		"queue": [
+			{"id": "1", "path": "/content/site/en", "type": "Activate", "numProcessed": 3, "lastProcessingError": "Connection refused"},
+			{"id": "2", "path": "/content/site/de", "type": "Activate", "numProcessed": 0}
+		]
+	}`), &queue))
+
+	a.False(queue.Empty())
+	a.True(queue.MetaData.Status.Blocked)
+	a.Len(queue.BlockedItems(), 1)
+	a.Equal("Connection refused", queue.LastError())
+}
@@ -2,8 +2,12 @@ package pkg
 
 import (
 	"fmt"
+	log "github.com/sirupsen/logrus"
 	"github.com/wttech/aemc/pkg/common"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"github.com/wttech/aemc/pkg/repl"
 	"golang.org/x/exp/maps"
+	"io"
 	"strings"
 )
 
@@ -79,6 +83,64 @@ func (ra ReplAgent) Delete() (bool, error) {
 	return true, nil
 }
 
+func (ra ReplAgent) Queue() (*repl.Queue, error) {
+	response, err := ra.page.repo.instance.http.Request().Get(ra.page.Content().Path() + ".queue.json")
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot read queue of replication agent '%s': %w", ra.instanceID(), ra.page.Path(), err)
+	} else if response.IsError() {
+		return nil, fmt.Errorf("%s > cannot read queue of replication agent '%s': %s", ra.instanceID(), ra.page.Path(), response.Status())
+	}
+	var queue repl.Queue
+	if err = fmtx.UnmarshalJSON(response.RawBody(), &queue); err != nil {
+		return nil, fmt.Errorf("%s > cannot parse queue of replication agent '%s': %w", ra.instanceID(), ra.page.Path(), err)
+	}
+	return &queue, nil
+}
+
+// QueueClear removes all items from queue (they will not be replicated)
+func (ra ReplAgent) QueueClear() error {
+	return ra.queueCommand("clear", "clearing", "cleared")
+}
+
+// QueueRetry forces replication of blocked items without waiting for retry delay
+func (ra ReplAgent) QueueRetry() error {
+	return ra.queueCommand("retry", "retrying", "retried")
+}
+
+func (ra ReplAgent) queueCommand(command string, verbDoing string, verbDone string) error {
+	log.Infof("%s > %s queue of replication agent '%s'", ra.instanceID(), verbDoing, ra.page.Path())
+	response, err := ra.page.repo.instance.http.Request().
+		SetFormData(map[string]string{"cmd": command}).
+		Post(ra.page.Content().Path() + ".queue.json")
+	if err != nil {
+		return fmt.Errorf("%s > cannot %s queue of replication agent '%s': %w", ra.instanceID(), command, ra.page.Path(), err)
+	} else if response.IsError() {
+		return fmt.Errorf("%s > cannot %s queue of replication agent '%s': %s", ra.instanceID(), command, ra.page.Path(), response.Status())
+	}
+	log.Infof("%s > %s queue of replication agent '%s'", ra.instanceID(), verbDone, ra.page.Path())
+	return nil
+}
+
+// TestConnection sends test replication request to agent transport; returns error when it is not successful
+func (ra ReplAgent) TestConnection() error {
+	log.Infof("%s > testing connection of replication agent '%s'", ra.instanceID(), ra.page.Path())
+	response, err := ra.page.repo.instance.http.Request().Get(ra.page.Content().Path() + ".test.html")
+	if err != nil {
+		return fmt.Errorf("%s > cannot test connection of replication agent '%s': %w", ra.instanceID(), ra.page.Path(), err)
+	} else if response.IsError() {
+		return fmt.Errorf("%s > cannot test connection of replication agent '%s': %s", ra.instanceID(), ra.page.Path(), response.Status())
+	}
+	htmlBytes, err := io.ReadAll(response.RawBody())
+	if err != nil {
+		return fmt.Errorf("%s > cannot read connection test result of replication agent '%s': %w", ra.instanceID(), ra.page.Path(), err)
+	}
+	if !strings.Contains(string(htmlBytes), "successful") {
+		return fmt.Errorf("%s > connection test of replication agent '%s' failed", ra.instanceID(), ra.page.Path())
+	}
+	log.Infof("%s > tested connection of replication agent '%s'", ra.instanceID(), ra.page.Path())
+	return nil
+}
+
 func (ra ReplAgent) instanceID() string {
 	return ra.page.repo.instance.id
 }
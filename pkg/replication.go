@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const (
+	ReplicationPath         = "/bin/replicate.json"
+	ReplicationTreePath     = "/libs/replication/treeactivation.html"
+	ReplicationAgentsAuthor = "author"
+)
+
+// Replication Facade for replicating (activating and deactivating) content using replication agent.
+type Replication struct {
+	instance *Instance
+
+	AgentName     string
+	QueueTimeout  time.Duration
+	QueueInterval time.Duration
+}
+
+func NewReplication(instance *Instance) *Replication {
+	cv := instance.manager.aem.config.Values()
+
+	return &Replication{
+		instance: instance,
+
+		AgentName:     cv.GetString("instance.replication.agent"),
+		QueueTimeout:  cv.GetDuration("instance.replication.queue.timeout"),
+		QueueInterval: cv.GetDuration("instance.replication.queue.interval"),
+	}
+}
+
+func (r *Replication) Agent() ReplAgent {
+	return r.instance.repo.ReplAgent(ReplicationAgentsAuthor, r.AgentName)
+}
+
+func (r *Replication) Activate(paths []string) error {
+	return r.replicate("Activate", paths)
+}
+
+func (r *Replication) Deactivate(paths []string) error {
+	return r.replicate("Deactivate", paths)
+}
+
+func (r *Replication) replicate(command string, paths []string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("%s > cannot replicate as no paths are specified", r.instance.ID())
+	}
+	pathList := strings.Join(paths, ", ")
+	log.Infof("%s > replicating (%s) paths '%s' using agent '%s'", r.instance.ID(), command, pathList, r.AgentName)
+	request := r.instance.http.Request()
+	request.FormData.Set("_charset_", "utf-8")
+	request.FormData.Set("cmd", command)
+	request.FormData.Set("agentId", r.AgentName)
+	for _, path := range paths {
+		request.FormData.Add("path", path)
+	}
+	response, err := request.Post(ReplicationPath)
+	if err != nil {
+		return fmt.Errorf("%s > cannot replicate (%s) paths '%s': %w", r.instance.ID(), command, pathList, err)
+	} else if response.IsError() {
+		return fmt.Errorf("%s > cannot replicate (%s) paths '%s': %s", r.instance.ID(), command, pathList, response.Status())
+	}
+	if err := r.AwaitQueueEmpty(); err != nil {
+		return err
+	}
+	log.Infof("%s > replicated (%s) paths '%s' using agent '%s'", r.instance.ID(), command, pathList, r.AgentName)
+	return nil
+}
+
+// TreeActivate replicates node with all descendants; when 'onlyModified' is set, up-to-date nodes are skipped
+func (r *Replication) TreeActivate(root string, onlyModified bool) error {
+	log.Infof("%s > activating tree '%s' using agent '%s'", r.instance.ID(), root, r.AgentName)
+	response, err := r.instance.http.Request().
+		SetFormData(map[string]string{
+			"_charset_":         "utf-8",
+			"cmd":               "activate",
+			"path":              root,
+			"onlymodified":      fmt.Sprintf("%t", onlyModified),
+			"ignoredeactivated": "false",
+			"reactivate":        "false",
+			"agentId":           r.AgentName,
+		}).
+		Post(ReplicationTreePath)
+	if err != nil {
+		return fmt.Errorf("%s > cannot activate tree '%s': %w", r.instance.ID(), root, err)
+	} else if response.IsError() {
+		return fmt.Errorf("%s > cannot activate tree '%s': %s", r.instance.ID(), root, response.Status())
+	}
+	if err := r.AwaitQueueEmpty(); err != nil {
+		return err
+	}
+	log.Infof("%s > activated tree '%s' using agent '%s'", r.instance.ID(), root, r.AgentName)
+	return nil
+}
+
+// AwaitQueueEmpty waits until all items from agent queue are replicated; fails fast when queue is blocked
+func (r *Replication) AwaitQueueEmpty() error {
+	agent := r.Agent()
+	started := time.Now()
+	for {
+		queue, err := agent.Queue()
+		if err != nil {
+			return err
+		}
+		if queue.Empty() {
+			return nil
+		}
+		if queue.MetaData.Status.Blocked {
+			return fmt.Errorf("%s > queue of replication agent '%s' is blocked (items: %d)", r.instance.ID(), r.AgentName, len(queue.Items))
+		}
+		if time.Now().After(started.Add(r.QueueTimeout)) {
+			return fmt.Errorf("%s > awaiting queue of replication agent '%s' to be empty reached timeout after %s (items: %d)", r.instance.ID(), r.AgentName, r.QueueTimeout, len(queue.Items))
+		}
+		log.Infof("%s > awaiting queue of replication agent '%s' to be empty (items: %d)", r.instance.ID(), r.AgentName, len(queue.Items))
+		time.Sleep(r.QueueInterval)
+	}
+}
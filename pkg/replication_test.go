@@ -0,0 +1,46 @@
+package pkg_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReplicationActivateAwaitsQueue(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var queueReads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case pkg.ReplicationPath:
+			a.NoError(r.ParseForm())
+			a.Equal("Activate", r.PostForm.Get("cmd"))
+			a.Equal("publish", r.PostForm.Get("agentId"))
+			a.Equal([]string{"/content/site/en", "/content/site/de"}, r.PostForm["path"])
+			_, _ = w.Write([]byte(`{}`))
+		case "/etc/replication/agents.author/publish/jcr:content.queue.json":
+			if atomic.AddInt32(&queueReads, 1) < 3 {
+				_, _ = w.Write([]byte(`{"metaData": {"queueStatus": {"agentName": "publish"}}, "queue": [{"id": "1", "path": "/content/site/en"}]}`))
+			} else {
+				_, _ = w.Write([]byte(`{"metaData": {"queueStatus": {"agentName": "publish"}}, "queue": []}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	instance := pkg.DefaultAEM().InstanceManager().New("local_author", server.URL, "admin", "admin")
+	replication := instance.Replication()
+	replication.AgentName = "publish"
+	replication.QueueInterval = time.Millisecond
+	replication.QueueTimeout = time.Second
+
+	a.NoError(replication.Activate([]string{"/content/site/en", "/content/site/de"}))
+	a.Equal(int32(3), atomic.LoadInt32(&queueReads))
+}
@@ -6,13 +6,20 @@ import (
 	"github.com/go-resty/resty/v2"
 	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
+	"github.com/wttech/aemc/pkg/common/filex"
 	"github.com/wttech/aemc/pkg/common/fmtx"
 	"github.com/wttech/aemc/pkg/common/mapsx"
+	"github.com/wttech/aemc/pkg/common/pathx"
+	"github.com/wttech/aemc/pkg/common/stringsx"
 	"github.com/wttech/aemc/pkg/repo"
+	"mime"
 	"net/http"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 )
 
 // Repo Facade for communicating with JCR repository.
@@ -20,6 +27,8 @@ type Repo struct {
 	instance Instance
 
 	PropertyChangeIgnored []string
+	TraversalConcurrency  int
+	TraversalBatchDepth   int
 }
 
 func NewRepo(i *Instance) *Repo {
@@ -29,6 +38,8 @@ func NewRepo(i *Instance) *Repo {
 		instance: *i,
 
 		PropertyChangeIgnored: cv.GetStringSlice("instance.repo.property_change_ignored"),
+		TraversalConcurrency:  cv.GetInt("instance.repo.traversal.concurrency"),
+		TraversalBatchDepth:   cv.GetInt("instance.repo.traversal.batch_depth"),
 	}
 }
 
@@ -109,6 +120,135 @@ func (r Repo) Move(sourcePath string, targetPath string, replace bool) error {
 	return nil
 }
 
+// UploadFile creates or replaces node of type 'nt:file' with content of local file
+func (r Repo) UploadFile(path string, localPath string, mimeType string) error {
+	log.Infof("%s > uploading file '%s' to node '%s'", r.instance.ID(), localPath, path)
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("%s > cannot open file '%s' to be uploaded to node '%s': %w", r.instance.ID(), localPath, path, err)
+	}
+	defer file.Close()
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(filepath.Ext(localPath))
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	name := stringsx.AfterLast(path, "/")
+	resp, err := r.instance.http.Request().
+		SetHeader("Accept", "application/json").
+		SetMultipartField(name, filepath.Base(localPath), mimeType, file).
+		SetMultipartFormData(map[string]string{name + "@TypeHint": "nt:file"}).
+		Post(stringsx.BeforeLast(path, "/"))
+	if err = r.handleResponse(fmt.Sprintf("%s > cannot upload file '%s' to node '%s'", r.instance.ID(), localPath, path), resp, err); err != nil {
+		return err
+	}
+	log.Infof("%s > uploaded file '%s' to node '%s'", r.instance.ID(), localPath, path)
+	return nil
+}
+
+// DownloadFile saves content of node of type 'nt:file' to local file
+func (r Repo) DownloadFile(path string, localPath string) error {
+	log.Infof("%s > downloading node '%s' to file '%s'", r.instance.ID(), path, localPath)
+	fileTmp := localPath + ".tmp"
+	if err := pathx.DeleteIfExists(fileTmp); err != nil {
+		return fmt.Errorf("%s > cannot delete temporary file for node '%s' downloaded to '%s': %w", r.instance.ID(), path, localPath, err)
+	}
+	defer func() { _ = pathx.DeleteIfExists(fileTmp) }()
+	response, err := r.instance.http.Request().Get(path)
+	if err != nil {
+		return fmt.Errorf("%s > cannot download node '%s': %w", r.instance.ID(), path, err)
+	}
+	defer response.RawBody().Close()
+	if response.IsError() {
+		return fmt.Errorf("%s > cannot download node '%s': %s", r.instance.ID(), path, response.Status())
+	}
+	if err = filex.WriteReader(fileTmp, response.RawBody()); err != nil {
+		return fmt.Errorf("%s > cannot download node '%s': %w", r.instance.ID(), path, err)
+	}
+	if err = os.Rename(fileTmp, localPath); err != nil {
+		return fmt.Errorf("%s > cannot move downloaded node from temporary path '%s' to target one '%s': %w", r.instance.ID(), fileTmp, localPath, err)
+	}
+	log.Infof("%s > downloaded node '%s' to file '%s'", r.instance.ID(), path, localPath)
+	return nil
+}
+
+// FileChecksum calculates checksum of content of node of type 'nt:file' (in the same way as for local files)
+func (r Repo) FileChecksum(path string) (string, error) {
+	response, err := r.instance.http.Request().Get(path)
+	if err != nil {
+		return "", fmt.Errorf("%s > cannot read file node '%s': %w", r.instance.ID(), path, err)
+	}
+	defer response.RawBody().Close()
+	if response.IsError() {
+		return "", fmt.Errorf("%s > cannot read file node '%s': %s", r.instance.ID(), path, response.Status())
+	}
+	checksum, err := filex.ChecksumReader(response.RawBody())
+	if err != nil {
+		return "", fmt.Errorf("%s > cannot calculate checksum of file node '%s': %w", r.instance.ID(), path, err)
+	}
+	return checksum, nil
+}
+
+// Query finds nodes using JCR-SQL2 statement (e.g. "SELECT * FROM [cq:Page] WHERE ISDESCENDANTNODE('/content/my-site')")
+func (r Repo) Query(sql2 string) ([]RepoNode, error) {
+	return r.QueryRange(sql2, 0, 0)
+}
+
+// QueryRange is like Query but returns only the page of results starting at offset (limit not greater than zero means no limit)
+// Paging is applied on the client side as the CRX/DE query endpoint always returns all results.
+func (r Repo) QueryRange(sql2 string, offset int, limit int) ([]RepoNode, error) {
+	log.Infof("%s > querying nodes using statement '%s'", r.instance.ID(), sql2)
+	response, err := r.instance.http.Request().
+		SetQueryParams(map[string]string{"_charset_": "UTF-8", "type": "JCR-SQL2", "stmt": sql2, "showResults": "true"}).
+		Get(QuerySQL2Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot query nodes using statement '%s': %w", r.instance.ID(), sql2, err)
+	} else if response.IsError() {
+		return nil, fmt.Errorf("%s > cannot query nodes using statement '%s': %s", r.instance.ID(), sql2, response.Status())
+	}
+	var result repo.SQL2Result
+	if err = fmtx.UnmarshalJSON(response.RawBody(), &result); err != nil {
+		return nil, fmt.Errorf("%s > cannot parse query response for statement '%s': %w", r.instance.ID(), sql2, err)
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("%s > cannot query nodes using statement '%s': %s", r.instance.ID(), sql2, result.ErrMsg)
+	}
+	hits := result.Results
+	if offset > 0 || limit > 0 {
+		hits = lo.Subset(hits, offset, uint(lo.Ternary(limit > 0, limit, len(hits))))
+	}
+	nodes := lo.Map(hits, func(hit repo.SQL2Hit, _ int) RepoNode { return r.Node(hit.Path) })
+	log.Infof("%s > queried nodes using statement '%s' (found: %d, returned: %d)", r.instance.ID(), sql2, len(result.Results), len(nodes))
+	return nodes, nil
+}
+
+// QueryBuilder finds nodes using QueryBuilder predicates (e.g. 'path=/content/my-site', 'type=cq:Page', 'p.limit=10')
+func (r Repo) QueryBuilder(params map[string]string) ([]RepoNode, error) {
+	log.Infof("%s > querying nodes using predicates '%v'", r.instance.ID(), params)
+	request := r.instance.http.Request().SetQueryParams(map[string]string{"p.hits": "selective", "p.properties": "jcr:path", "p.limit": "-1"})
+	request.SetQueryParams(params)
+	response, err := request.Get(QueryBuilderPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot query nodes using predicates '%v': %w", r.instance.ID(), params, err)
+	} else if response.IsError() {
+		return nil, fmt.Errorf("%s > cannot query nodes using predicates '%v': %s", r.instance.ID(), params, response.Status())
+	}
+	var result repo.QueryBuilderResult
+	if err = fmtx.UnmarshalJSON(response.RawBody(), &result); err != nil {
+		return nil, fmt.Errorf("%s > cannot parse query response for predicates '%v': %w", r.instance.ID(), params, err)
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("%s > cannot query nodes using predicates '%v'", r.instance.ID(), params)
+	}
+	nodes := lo.FilterMap(result.Hits, func(hit map[string]any, _ int) (RepoNode, bool) {
+		path, ok := hit["jcr:path"].(string)
+		return r.Node(path), ok
+	})
+	log.Infof("%s > queried nodes using predicates '%v' (found: %d)", r.instance.ID(), params, len(nodes))
+	return nodes, nil
+}
+
 func (r Repo) requestFormData(operation string, props map[string]any) *resty.Request {
 	request := r.instance.http.Request()
 	request.SetHeader("Accept", "application/json")
@@ -116,6 +256,14 @@ func (r Repo) requestFormData(operation string, props map[string]any) *resty.Req
 	for k, v := range props {
 		if v == nil {
 			request.FormData.Set(fmt.Sprintf("%s@Delete", k), "")
+		} else if date, ok := v.(time.Time); ok {
+			request.FormData.Set(k, date.Format(DocViewDateLayout))
+			request.FormData.Set(fmt.Sprintf("%s@TypeHint", k), "Date")
+		} else if dates, ok := v.([]time.Time); ok {
+			for _, date := range dates {
+				request.FormData.Add(k, date.Format(DocViewDateLayout))
+			}
+			request.FormData.Set(fmt.Sprintf("%s@TypeHint", k), "Date[]")
 		} else {
 			rv := reflect.ValueOf(v)
 			if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
@@ -133,9 +281,45 @@ func (r Repo) requestFormData(operation string, props map[string]any) *resty.Req
 }
 
 func (r Repo) PropsEqual(current map[string]any, updated map[string]any) bool {
-	return mapsx.EqualIgnoring(current, updated, r.PropertyChangeIgnored)
+	return mapsx.EqualIgnoring(current, propsAsJSON(updated), r.PropertyChangeIgnored)
 }
 
+// propsAsJSON converts values to types returned by Sling when reading properties (e.g. dates formatted, numbers as floats, arrays as []any) so that they could be compared
+func propsAsJSON(props map[string]any) map[string]any {
+	return lo.MapValues(props, func(value any, _ string) any { return propValueAsJSON(value) })
+}
+
+func propValueAsJSON(value any) any {
+	switch typed := value.(type) {
+	case time.Time:
+		return typed.Format(PropDateLayoutJSON)
+	case int:
+		return float64(typed)
+	case int32:
+		return float64(typed)
+	case int64:
+		return float64(typed)
+	case float32:
+		return float64(typed)
+	case []any:
+		return lo.Map(typed, func(item any, _ int) any { return propValueAsJSON(item) })
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		result := make([]any, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			result[i] = propValueAsJSON(rv.Index(i).Interface())
+		}
+		return result
+	}
+	return value
+}
+
+const (
+	QuerySQL2Path    = "/crx/de/query.jsp"
+	QueryBuilderPath = "/bin/querybuilder.json"
+)
+
 func propTypeHint(kind reflect.Kind) string {
 	switch kind {
 	case reflect.Bool:
@@ -168,7 +352,7 @@ func (r Repo) handleResponse(action string, resp *resty.Response, err error) err
 func NewRepoNodeList(nodes []RepoNode) NodeList {
 	var sortedNodes []RepoNode
 	sortedNodes = append(sortedNodes, nodes...)
-	sort.SliceStable(sortedNodes, func(i, j int) bool { return strings.Compare(nodes[i].Name(), nodes[j].Name()) < 0 })
+	sort.SliceStable(sortedNodes, func(i, j int) bool { return strings.Compare(sortedNodes[i].path, sortedNodes[j].path) < 0 })
 	return NodeList{Nodes: sortedNodes, Total: len(nodes)}
 }
 
@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"bytes"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+)
+
+// BulkReport summarizes changes made by operation applied to many nodes
+type BulkReport struct {
+	DryRun  bool     `json:"dryRun" yaml:"dry_run"`
+	Matched []string `json:"matched" yaml:"matched"`
+	Changed []string `json:"changed" yaml:"changed"`
+}
+
+func (r BulkReport) MarshalText() string {
+	bs := bytes.NewBufferString("")
+	bs.WriteString(fmtx.TblMap("summary", "name", "value", map[string]any{
+		"dry run": r.DryRun,
+		"matched": len(r.Matched),
+		"changed": len(r.Changed),
+	}))
+	bs.WriteString(fmtx.TblRows("nodes", true, []string{"path", "changed"}, lo.Map(r.Matched, func(path string, _ int) map[string]any {
+		return map[string]any{"path": path, "changed": lo.Contains(r.Changed, path)}
+	})))
+	return bs.String()
+}
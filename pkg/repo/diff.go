@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"sort"
+	"strings"
+)
+
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// Diff describes differences between subtrees of two instances (source is treated as a base, target as a new state)
+type Diff struct {
+	Path   string     `json:"path" yaml:"path"`
+	Source string     `json:"source" yaml:"source"`
+	Target string     `json:"target" yaml:"target"`
+	Nodes  []NodeDiff `json:"nodes" yaml:"nodes"`
+}
+
+type NodeDiff struct {
+	Path  string     `json:"path" yaml:"path"`
+	Type  string     `json:"type" yaml:"type"`
+	Props []PropDiff `json:"props,omitempty" yaml:"props,omitempty"`
+}
+
+type PropDiff struct {
+	Name   string `json:"name" yaml:"name"`
+	Type   string `json:"type" yaml:"type"`
+	Source any    `json:"source,omitempty" yaml:"source,omitempty"`
+	Target any    `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+// NewDiff compares properties of nodes keyed by path; ignored properties are skipped
+func NewDiff(path string, source string, target string, sourceNodes map[string]map[string]any, targetNodes map[string]map[string]any, ignored []string) Diff {
+	result := Diff{Path: path, Source: source, Target: target, Nodes: []NodeDiff{}}
+	paths := lo.Uniq(append(lo.Keys(sourceNodes), lo.Keys(targetNodes)...))
+	sort.Strings(paths)
+	for _, nodePath := range paths {
+		sourceProps, sourceExists := sourceNodes[nodePath]
+		targetProps, targetExists := targetNodes[nodePath]
+		nodeDiff := NodeDiff{Path: nodePath}
+		switch {
+		case !sourceExists:
+			nodeDiff.Type = DiffAdded
+		case !targetExists:
+			nodeDiff.Type = DiffRemoved
+		default:
+			nodeDiff.Type = DiffChanged
+		}
+		nodeDiff.Props = diffProps(sourceProps, targetProps, ignored)
+		if nodeDiff.Type == DiffChanged && len(nodeDiff.Props) == 0 {
+			continue
+		}
+		result.Nodes = append(result.Nodes, nodeDiff)
+	}
+	return result
+}
+
+func diffProps(source map[string]any, target map[string]any, ignored []string) []PropDiff {
+	var result []PropDiff
+	names := lo.Uniq(append(lo.Keys(source), lo.Keys(target)...))
+	sort.Strings(names)
+	for _, name := range names {
+		if lo.Contains(ignored, name) {
+			continue
+		}
+		sourceValue, sourceExists := source[name]
+		targetValue, targetExists := target[name]
+		switch {
+		case !sourceExists:
+			result = append(result, PropDiff{Name: name, Type: DiffAdded, Target: targetValue})
+		case !targetExists:
+			result = append(result, PropDiff{Name: name, Type: DiffRemoved, Source: sourceValue})
+		case !cmp.Equal(sourceValue, targetValue):
+			result = append(result, PropDiff{Name: name, Type: DiffChanged, Source: sourceValue, Target: targetValue})
+		}
+	}
+	return result
+}
+
+func (d Diff) Empty() bool {
+	return len(d.Nodes) == 0
+}
+
+// NodesOfType returns node differences of given type (e.g. 'added')
+func (d Diff) NodesOfType(diffType string) []NodeDiff {
+	return lo.Filter(d.Nodes, func(n NodeDiff, _ int) bool { return n.Type == diffType })
+}
+
+// Unified renders differences similarly to 'diff -u' (lines prefixed with '-' exist only on source, '+' only on target)
+func (d Diff) Unified() string {
+	bs := bytes.NewBufferString("")
+	bs.WriteString(fmt.Sprintf("--- %s:%s\n", d.Source, d.Path))
+	bs.WriteString(fmt.Sprintf("+++ %s:%s\n", d.Target, d.Path))
+	for _, node := range d.Nodes {
+		bs.WriteString(fmt.Sprintf("@@ %s (%s) @@\n", node.Path, node.Type))
+		for _, prop := range node.Props {
+			switch prop.Type {
+			case DiffAdded:
+				bs.WriteString(fmt.Sprintf("+ %s = %s\n", prop.Name, diffValue(prop.Target)))
+			case DiffRemoved:
+				bs.WriteString(fmt.Sprintf("- %s = %s\n", prop.Name, diffValue(prop.Source)))
+			case DiffChanged:
+				bs.WriteString(fmt.Sprintf("- %s = %s\n", prop.Name, diffValue(prop.Source)))
+				bs.WriteString(fmt.Sprintf("+ %s = %s\n", prop.Name, diffValue(prop.Target)))
+			}
+		}
+	}
+	return bs.String()
+}
+
+func diffValue(value any) string {
+	if values, ok := value.([]any); ok {
+		return "[" + strings.Join(lo.Map(values, func(v any, _ int) string { return fmt.Sprintf("%v", v) }), ", ") + "]"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func (d Diff) MarshalText() string {
+	bs := bytes.NewBufferString("")
+	bs.WriteString(fmtx.TblMap("summary", "nodes", "count", map[string]any{
+		DiffAdded:   len(d.NodesOfType(DiffAdded)),
+		DiffRemoved: len(d.NodesOfType(DiffRemoved)),
+		DiffChanged: len(d.NodesOfType(DiffChanged)),
+	}))
+	bs.WriteString("\n")
+	if d.Empty() {
+		bs.WriteString("no differences\n")
+	} else {
+		bs.WriteString(d.Unified())
+	}
+	return bs.String()
+}
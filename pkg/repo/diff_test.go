@@ -0,0 +1,33 @@
+package repo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg/repo"
+	"testing"
+)
+
+func TestNewDiff(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	source := map[string]map[string]any{
+		"/conf/site":         {"jcr:primaryType": "sling:Folder", "jcr:created": "2023-01-01"},
+		"/conf/site/old":     {"jcr:primaryType": "nt:unstructured"},
+		"/conf/site/changed": {"jcr:primaryType": "nt:unstructured", "title": "Old", "tags": []any{"a", "b"}},
+	}
+	target := map[string]map[string]any{
+		"/conf/site":         {"jcr:primaryType": "sling:Folder", "jcr:created": "2023-02-02"},
+		"/conf/site/new":     {"jcr:primaryType": "nt:unstructured"},
+		"/conf/site/changed": {"jcr:primaryType": "nt:unstructured", "title": "New", "tags": []any{"a", "b"}, "extra": true},
+	}
+	diff := repo.NewDiff("/conf/site", "local_author", "remote_author", source, target, []string{"jcr:created"})
+
+	a.Len(diff.Nodes, 3)
+	a.Equal([]repo.NodeDiff{{Path: "/conf/site/new", Type: repo.DiffAdded, Props: []repo.PropDiff{{Name: "jcr:primaryType", Type: repo.DiffAdded, Target: "nt:unstructured"}}}}, diff.NodesOfType(repo.DiffAdded))
+	a.Equal("/conf/site/old", diff.NodesOfType(repo.DiffRemoved)[0].Path)
+	a.Equal([]repo.PropDiff{
+		{Name: "extra", Type: repo.DiffAdded, Target: true},
+		{Name: "title", Type: repo.DiffChanged, Source: "Old", Target: "New"},
+	}, diff.NodesOfType(repo.DiffChanged)[0].Props)
+	a.Contains(diff.Unified(), "@@ /conf/site/changed (changed) @@\n+ extra = true\n- title = Old\n+ title = New\n")
+}
@@ -0,0 +1,52 @@
+package repo
+
+import (
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"sort"
+)
+
+// SQL2Result is a response of CRX/DE query endpoint
+type SQL2Result struct {
+	Success bool      `json:"success"`
+	Results []SQL2Hit `json:"results"`
+	Total   int       `json:"total"`
+	ErrMsg  string    `json:"errorMessage"`
+}
+
+type SQL2Hit struct {
+	Path string `json:"path"`
+}
+
+// QueryBuilderResult is a response of QueryBuilder JSON servlet
+type QueryBuilderResult struct {
+	Success bool             `json:"success"`
+	Results int              `json:"results"`
+	Total   int              `json:"total"`
+	More    bool             `json:"more"`
+	Offset  int              `json:"offset"`
+	Hits    []map[string]any `json:"hits"`
+}
+
+type QueryHit struct {
+	Path       string         `json:"path" yaml:"path"`
+	Properties map[string]any `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+// QueryHits is a page of query results; count is the number of hits on the page (not the total number of matching nodes)
+type QueryHits struct {
+	Count int        `json:"count" yaml:"count"`
+	Hits  []QueryHit `json:"hits" yaml:"hits"`
+}
+
+func (qh QueryHits) MarshalText() string {
+	propNames := lo.Uniq(lo.FlatMap(qh.Hits, func(h QueryHit, _ int) []string { return lo.Keys(h.Properties) }))
+	sort.Strings(propNames)
+	return fmtx.TblRows("hits", true, append([]string{"path"}, propNames...), lo.Map(qh.Hits, func(h QueryHit, _ int) map[string]any {
+		row := map[string]any{"path": h.Path}
+		for _, name := range propNames {
+			row[name] = h.Properties[name]
+		}
+		return row
+	}))
+}
@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"fmt"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"github.com/wttech/aemc/pkg/common/lox"
+	"github.com/wttech/aemc/pkg/repo"
+)
+
+// RepoBulk applies same operation to all nodes found by traversing from the root node and matching properties
+type RepoBulk struct {
+	Root        RepoNode
+	Match       map[string]string
+	Concurrency int
+	DryRun      bool
+}
+
+// Matches checks if all expected properties have given values (for multi-valued properties one of values is enough)
+func (b RepoBulk) Matches(props map[string]any) bool {
+	return lo.EveryBy(lo.Keys(b.Match), func(name string) bool {
+		value, ok := props[name]
+		if !ok {
+			return false
+		}
+		if values, ok := value.([]any); ok {
+			return lo.SomeBy(values, func(v any) bool { return fmt.Sprintf("%v", v) == b.Match[name] })
+		}
+		return fmt.Sprintf("%v", value) == b.Match[name]
+	})
+}
+
+func (b RepoBulk) Find() ([]RepoNode, error) {
+	traversal := b.Root.Traversal()
+	traversal.Concurrency = b.concurrency()
+	items, err := traversal.Collect()
+	if err != nil {
+		return nil, err
+	}
+	return lo.FilterMap(items, func(item RepoTraversalResult, _ int) (RepoNode, bool) {
+		return item.Node, b.Matches(item.Props)
+	}), nil
+}
+
+// concurrency falls back to configured traversal concurrency when not set explicitly
+func (b RepoBulk) concurrency() int {
+	if b.Concurrency > 0 {
+		return b.Concurrency
+	}
+	return b.Root.repo.TraversalConcurrency
+}
+
+func (b RepoBulk) UpdateAllWithChanged(props map[string]any) (*repo.BulkReport, error) {
+	log.Infof("%s > updating nodes under '%s' matching '%v'", b.Root.repo.instance.ID(), b.Root.path, b.Match)
+	report, err := b.apply(false, func(node RepoNode) (bool, error) { return node.SaveWithChanged(props) })
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot update nodes under '%s': %w", b.Root.repo.instance.ID(), b.Root.path, err)
+	}
+	log.Infof("%s > updated nodes under '%s' matching '%v' (changed: %d)", b.Root.repo.instance.ID(), b.Root.path, b.Match, len(report.Changed))
+	return report, nil
+}
+
+func (b RepoBulk) DeleteAllWithChanged() (*repo.BulkReport, error) {
+	log.Infof("%s > deleting nodes under '%s' matching '%v'", b.Root.repo.instance.ID(), b.Root.path, b.Match)
+	report, err := b.apply(true, func(node RepoNode) (bool, error) { return node.DeleteWithChanged() })
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot delete nodes under '%s': %w", b.Root.repo.instance.ID(), b.Root.path, err)
+	}
+	log.Infof("%s > deleted nodes under '%s' matching '%v' (changed: %d)", b.Root.repo.instance.ID(), b.Root.path, b.Match, len(report.Changed))
+	return report, nil
+}
+
+// apply runs action on matching nodes; when pruning then descendants of matching nodes are skipped (e.g. already deleted)
+func (b RepoBulk) apply(prune bool, action func(node RepoNode) (bool, error)) (*repo.BulkReport, error) {
+	nodes, err := b.Find()
+	if err != nil {
+		return nil, err
+	}
+	if prune {
+		nodes = lo.Filter(nodes, func(node RepoNode, _ int) bool {
+			return !lo.SomeBy(nodes, func(other RepoNode) bool { return other.path != node.path && node.IsSelfOrDescendantOf(other) })
+		})
+	}
+	report := &repo.BulkReport{
+		DryRun:  b.DryRun,
+		Matched: lo.Map(nodes, func(n RepoNode, _ int) string { return n.path }),
+		Changed: []string{},
+	}
+	if b.DryRun {
+		return report, nil
+	}
+	changed, err := lox.LimitedMap(lo.Max([]int{b.concurrency(), 1}), nodes, func(node RepoNode) (bool, error) { return action(node) })
+	if err != nil {
+		return nil, err
+	}
+	for i, node := range nodes {
+		if changed[i] {
+			report.Changed = append(report.Changed, node.path)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,282 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DocViewFile = ".content.xml"
+
+	// DocViewDateLayout is ISO 8601 format used by FileVault for values of type 'Date'
+	DocViewDateLayout = "2006-01-02T15:04:05.000-07:00"
+
+	// PropDateLayoutJSON is format of dates returned by Sling default GET servlet (e.g. 'Mon Jan 02 2023 10:00:00 GMT+0100')
+	PropDateLayoutJSON = "Mon Jan 02 2006 15:04:05 GMT-0700"
+)
+
+// docViewNamespaces are declared in '.content.xml' files when properties are using given prefixes
+var docViewNamespaces = map[string]string{
+	"jcr":     "http://www.jcp.org/jcr/1.0",
+	"nt":      "http://www.jcp.org/jcr/nt/1.0",
+	"mix":     "http://www.jcp.org/jcr/mix/1.0",
+	"sling":   "http://sling.apache.org/jcr/sling/1.0",
+	"cq":      "http://www.day.com/jcr/cq/1.0",
+	"dam":     "http://www.day.com/dam/1.0",
+	"rep":     "internal",
+	"oak":     "http://jackrabbit.apache.org/oak/ns/1.0",
+	"granite": "http://www.adobe.com/jcr/granite/1.0",
+	"vlt":     "http://www.day.com/jcr/vault/1.0",
+}
+
+// docViewPropsSkipped are protected or computed properties which cannot be saved back to repository
+var docViewPropsSkipped = []string{"jcr:created", "jcr:createdBy", "jcr:uuid", "jcr:baseVersion", "jcr:predecessors", "jcr:versionHistory", "jcr:isCheckedOut"}
+
+// MarshalDocView serializes node properties to FileVault document view format used by '.content.xml' files
+func MarshalDocView(props map[string]any) string {
+	names := lo.Filter(lo.Keys(props), func(name string, _ int) bool {
+		return !strings.HasPrefix(name, ":") && !lo.Contains(docViewPropsSkipped, name)
+	})
+	sort.SliceStable(names, func(i, j int) bool { return docViewPropOrder(names[i]) < docViewPropOrder(names[j]) })
+	prefixes := lo.Uniq(append([]string{"jcr"}, lo.FilterMap(names, func(name string, _ int) (string, bool) {
+		prefix, _, ok := strings.Cut(name, ":")
+		return prefix, ok
+	})...))
+	sort.Strings(prefixes)
+
+	bs := bytes.NewBufferString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<jcr:root")
+	for _, prefix := range prefixes {
+		if uri, ok := docViewNamespaces[prefix]; ok {
+			bs.WriteString(fmt.Sprintf(" xmlns:%s=\"%s\"", prefix, uri))
+		}
+	}
+	for _, name := range names {
+		bs.WriteString(fmt.Sprintf("\n    %s=\"%s\"", name, fmtx.EscapeXML(docViewValue(props[name]))))
+	}
+	bs.WriteString("/>\n")
+	return bs.String()
+}
+
+func docViewPropOrder(name string) string {
+	switch name {
+	case "jcr:primaryType":
+		return "0"
+	case "jcr:mixinTypes":
+		return "1"
+	default:
+		return "2" + name
+	}
+}
+
+func docViewValue(value any) string {
+	value = docViewDate(value)
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		var items []string
+		hint := ""
+		for i := 0; i < rv.Len(); i++ {
+			item := docViewDate(rv.Index(i).Interface())
+			hint = docViewTypeHint(item)
+			items = append(items, strings.ReplaceAll(docViewScalar(item), ",", "\\,"))
+		}
+		return docViewHinted(hint, "["+strings.Join(items, ",")+"]")
+	}
+	return docViewHinted(docViewTypeHint(value), docViewEscapeString(docViewScalar(value)))
+}
+
+func docViewHinted(hint string, value string) string {
+	if hint == "" || hint == "String" {
+		return value
+	}
+	return fmt.Sprintf("{%s}%s", hint, value)
+}
+
+// docViewDate recognizes dates read from JSON as strings so that their type is not lost
+func docViewDate(value any) any {
+	if text, ok := value.(string); ok {
+		if date, err := time.Parse(PropDateLayoutJSON, text); err == nil {
+			return date
+		}
+	}
+	return value
+}
+
+// docViewTypeHint is like propTypeHint but recognizes integral numbers read from JSON as longs
+func docViewTypeHint(value any) string {
+	if _, ok := value.(time.Time); ok {
+		return "Date"
+	}
+	if number, ok := value.(float64); ok && number == math.Trunc(number) {
+		return propTypeHint(reflect.Int64)
+	}
+	if value == nil {
+		return ""
+	}
+	return propTypeHint(reflect.ValueOf(value).Kind())
+}
+
+func docViewScalar(value any) string {
+	if date, ok := value.(time.Time); ok {
+		return date.Format(DocViewDateLayout)
+	}
+	if number, ok := value.(float64); ok && number == math.Trunc(number) {
+		return strconv.FormatInt(int64(number), 10)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func docViewEscapeString(value string) string {
+	if strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[") {
+		return "\\" + value
+	}
+	return value
+}
+
+// UnmarshalDocView reads node properties from FileVault document view format (only root element is considered)
+func UnmarshalDocView(reader io.Reader) (map[string]any, error) {
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("cannot find root element in document view")
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot parse document view: %w", err)
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		props := map[string]any{}
+		for _, attr := range element.Attr {
+			if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+				continue
+			}
+			name := attr.Name.Local
+			if attr.Name.Space != "" {
+				name = docViewPrefix(attr.Name.Space) + ":" + attr.Name.Local
+			}
+			props[name] = parseDocViewValue(attr.Value)
+		}
+		return props, nil
+	}
+}
+
+func docViewPrefix(space string) string {
+	prefix, ok := lo.FindKey(docViewNamespaces, space)
+	if ok {
+		return prefix
+	}
+	return space
+}
+
+func parseDocViewValue(value string) any {
+	hint := ""
+	if strings.HasPrefix(value, "{") {
+		if end := strings.Index(value, "}"); end > 0 {
+			hint = value[1:end]
+			value = value[end+1:]
+		}
+	}
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		items := splitDocViewArray(value[1 : len(value)-1])
+		switch hint {
+		case "Boolean":
+			return lo.Map(items, func(item string, _ int) bool { return parseDocViewScalar(hint, item).(bool) })
+		case "Long":
+			return lo.Map(items, func(item string, _ int) int64 { return parseDocViewScalar(hint, item).(int64) })
+		case "Decimal", "Double":
+			return lo.Map(items, func(item string, _ int) float64 { return parseDocViewScalar(hint, item).(float64) })
+		case "Date":
+			dates := lo.FilterMap(items, func(item string, _ int) (time.Time, bool) {
+				date, ok := parseDocViewScalar(hint, item).(time.Time)
+				return date, ok
+			})
+			if len(dates) == len(items) {
+				return dates
+			}
+			return items
+		default:
+			return items
+		}
+	}
+	return parseDocViewScalar(hint, strings.TrimPrefix(value, "\\"))
+}
+
+func parseDocViewScalar(hint string, value string) any {
+	switch hint {
+	case "Boolean":
+		result, _ := strconv.ParseBool(value)
+		return result
+	case "Long":
+		result, _ := strconv.ParseInt(value, 10, 64)
+		return result
+	case "Decimal", "Double":
+		result, _ := strconv.ParseFloat(value, 64)
+		return result
+	case "Date":
+		result, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return value
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+func splitDocViewArray(value string) []string {
+	result := []string{}
+	if value == "" {
+		return result
+	}
+	current := strings.Builder{}
+	escaped := false
+	for _, char := range value {
+		if escaped {
+			current.WriteRune(char)
+			escaped = false
+		} else if char == '\\' {
+			escaped = true
+		} else if char == ',' {
+			result = append(result, current.String())
+			current.Reset()
+		} else {
+			current.WriteRune(char)
+		}
+	}
+	return append(result, current.String())
+}
+
+// EscapeNodeName converts node name to file name as FileVault does (e.g. 'jcr:content' to '_jcr_content')
+func EscapeNodeName(name string) string {
+	if prefix, local, ok := strings.Cut(name, ":"); ok {
+		return "_" + prefix + "_" + local
+	}
+	if strings.HasPrefix(name, "_") {
+		return "_" + name
+	}
+	return name
+}
+
+// UnescapeNodeName converts file name back to node name (e.g. '_jcr_content' to 'jcr:content')
+func UnescapeNodeName(name string) string {
+	if strings.HasPrefix(name, "__") {
+		return name[1:]
+	}
+	if strings.HasPrefix(name, "_") {
+		if prefix, local, ok := strings.Cut(name[1:], "_"); ok {
+			return prefix + ":" + local
+		}
+	}
+	return name
+}
@@ -0,0 +1,47 @@
+package pkg_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocViewRoundTrip(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	text := pkg.MarshalDocView(map[string]any{
+		"jcr:primaryType":    "nt:unstructured",
+		"jcr:created":        "Mon Jan 02 2023 10:00:00 GMT+0100",
+		"sling:resourceType": "my-app/components/page",
+		"enabled":            true,
+		"count":              float64(5),
+		"ratio":              1.5,
+		"tags":               []any{"a", "b,c"},
+		"braced":             "{not a hint}",
+		"cq:lastModified":    "Tue Mar 14 2023 09:30:00 GMT+0100",
+	})
+	a.Contains(text, `xmlns:sling="http://sling.apache.org/jcr/sling/1.0"`)
+	a.Contains(text, `count="{Long}5"`)
+	a.Contains(text, `cq:lastModified="{Date}2023-03-14T09:30:00.000+01:00"`)
+	a.NotContains(text, "jcr:created")
+
+	props, err := pkg.UnmarshalDocView(strings.NewReader(text))
+	a.Nil(err)
+	a.Equal(map[string]any{
+		"jcr:primaryType":    "nt:unstructured",
+		"sling:resourceType": "my-app/components/page",
+		"enabled":            true,
+		"count":              int64(5),
+		"ratio":              1.5,
+		"tags":               []string{"a", "b,c"},
+		"braced":             "{not a hint}",
+		"cq:lastModified":    time.Date(2023, 3, 14, 9, 30, 0, 0, time.FixedZone("", 3600)),
+	}, props)
+
+	a.Equal("_jcr_content", pkg.EscapeNodeName("jcr:content"))
+	a.Equal("jcr:content", pkg.UnescapeNodeName("_jcr_content"))
+	a.Equal("_private", pkg.UnescapeNodeName(pkg.EscapeNodeName("_private")))
+}
@@ -100,3 +100,22 @@ func TestRepoTraverse(t *testing.T) {
 	}
 	a.GreaterOrEqual(traversed, 20)
 }
+
+func TestRepoTraversal(t *testing.T) {
+	t.Parallel()
+
+	a := assert.New(t)
+	aem := pkg.NewAem()
+
+	instance := aem.InstanceManager().NewLocalAuthor()
+	traversal := instance.Repo().Node("/etc/dam").Traversal()
+	traversal.MaxDepth = 2
+
+	items, err := traversal.Collect()
+	a.Nil(err)
+	a.GreaterOrEqual(len(items), 2)
+	for _, item := range items {
+		a.LessOrEqual(item.Depth, 2)
+		a.NotEmpty(item.Props["jcr:primaryType"])
+	}
+}
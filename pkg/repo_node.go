@@ -5,10 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"github.com/wttech/aemc/pkg/common/filex"
 	"github.com/wttech/aemc/pkg/common/fmtx"
 	"github.com/wttech/aemc/pkg/common/langx"
+	"github.com/wttech/aemc/pkg/common/pathx"
 	"github.com/wttech/aemc/pkg/common/stringsx"
+	"github.com/wttech/aemc/pkg/repo"
 	"golang.org/x/exp/maps"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -80,6 +87,10 @@ func (n RepoNode) Breadcrumb() []RepoNode {
 	return lo.Reverse(result)
 }
 
+func (n RepoNode) IsSelfOrDescendantOf(ancestor RepoNode) bool {
+	return ancestor.Root() || n.path == ancestor.path || strings.HasPrefix(n.path, ancestor.path+"/")
+}
+
 func (n RepoNode) Child(name string) RepoNode {
 	return NewNode(n.repo, fmt.Sprintf("%s/%s", n.path, name))
 }
@@ -346,3 +357,200 @@ func (i *RepoNodeTraversor) Next() (RepoNode, bool, error) {
 	}
 	return current, true, nil
 }
+
+func (n RepoNode) UploadFile(localPath string, mimeType string) error {
+	if !pathx.Exists(localPath) || pathx.IsDir(localPath) {
+		return fmt.Errorf("%s > file '%s' cannot be uploaded to node '%s' as it does not exist", n.repo.instance.ID(), localPath, n.path)
+	}
+	return n.repo.UploadFile(n.path, localPath, mimeType)
+}
+
+// UploadFileWithChanged uploads file only when node does not exist or its content differs (sizes or checksums are compared)
+func (n RepoNode) UploadFileWithChanged(localPath string, mimeType string) (bool, error) {
+	upToDate, err := n.FileEquals(localPath)
+	if err != nil {
+		return false, err
+	}
+	if upToDate {
+		return false, nil
+	}
+	return true, n.UploadFile(localPath, mimeType)
+}
+
+// FileEquals checks if node of type 'nt:file' has the same content as local file
+func (n RepoNode) FileEquals(localPath string) (bool, error) {
+	exists, err := n.Content().Exists()
+	if err != nil || !exists {
+		return false, err
+	}
+	contentProps, err := n.Content().ReadProps()
+	if err != nil {
+		return false, err
+	}
+	localStat, err := os.Stat(localPath)
+	if err != nil {
+		return false, fmt.Errorf("%s > cannot read file '%s' to be compared with node '%s': %w", n.repo.instance.ID(), localPath, n.path, err)
+	}
+	if remoteSize, ok := contentProps[":jcr:data"].(float64); ok && int64(remoteSize) != localStat.Size() {
+		return false, nil
+	}
+	localChecksum, err := filex.ChecksumFile(localPath)
+	if err != nil {
+		return false, err
+	}
+	remoteChecksum, err := n.repo.FileChecksum(n.path)
+	if err != nil {
+		return false, err
+	}
+	return localChecksum == remoteChecksum, nil
+}
+
+func (n RepoNode) DownloadFile(localPath string) error {
+	exists, err := n.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%s > node '%s' cannot be downloaded as it does not exist", n.repo.instance.ID(), n.path)
+	}
+	return n.repo.DownloadFile(n.path, localPath)
+}
+
+// Diff compares node and its descendants with other node (e.g. the same path on another instance)
+func (n RepoNode) Diff(target RepoNode) (*repo.Diff, error) {
+	log.Infof("%s > comparing node '%s' with node '%s' on instance '%s'", n.repo.instance.ID(), n.path, target.path, target.repo.instance.ID())
+	sourceNodes, err := n.subtreeProps(n.path)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot compare node '%s': %w", n.repo.instance.ID(), n.path, err)
+	}
+	targetNodes, err := target.subtreeProps(n.path)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot compare node '%s': %w", target.repo.instance.ID(), target.path, err)
+	}
+	diff := repo.NewDiff(n.path, n.repo.instance.ID(), target.repo.instance.ID(), sourceNodes, targetNodes, n.repo.PropertyChangeIgnored)
+	log.Infof("%s > compared node '%s' with node '%s' on instance '%s' (differences: %d)", n.repo.instance.ID(), n.path, target.path, target.repo.instance.ID(), len(diff.Nodes))
+	return &diff, nil
+}
+
+// subtreeProps reads properties of node and its descendants keyed by paths relative to node prefixed with given root
+func (n RepoNode) subtreeProps(root string) (map[string]map[string]any, error) {
+	result := map[string]map[string]any{}
+	exists, err := n.Exists()
+	if err != nil || !exists {
+		return result, err
+	}
+	items, err := n.Traversal().Collect()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		result[root+strings.TrimPrefix(item.Node.path, n.path)] = item.Props
+	}
+	return result, nil
+}
+
+// Export saves node and its descendants to dir structured like FileVault 'jcr_root' (each node as '.content.xml' file)
+// Nodes of type 'nt:file' (with their descendants) are skipped as binaries cannot be saved back by import.
+func (n RepoNode) Export(dir string) ([]string, error) {
+	log.Infof("%s > exporting node '%s' to dir '%s'", n.repo.instance.ID(), n.path, dir)
+	var paths []string
+	var fileNodes []RepoNode
+	traversor := n.Traversor()
+	for {
+		node, ok, err := traversor.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%s > cannot export node '%s': %w", n.repo.instance.ID(), n.path, err)
+		}
+		if !ok {
+			break
+		}
+		if lo.SomeBy(fileNodes, func(fileNode RepoNode) bool { return node.IsSelfOrDescendantOf(fileNode) }) {
+			continue
+		}
+		props, err := node.ReadProps()
+		if err != nil {
+			return nil, fmt.Errorf("%s > cannot export node '%s': %w", n.repo.instance.ID(), node.path, err)
+		}
+		if props["jcr:primaryType"] == "nt:file" {
+			log.Warnf("%s > skipping exporting node '%s' as binary files are not supported", n.repo.instance.ID(), node.path)
+			fileNodes = append(fileNodes, node)
+			continue
+		}
+		file := filepath.Join(dir, node.fileDir(), DocViewFile)
+		if err := filex.WriteString(file, MarshalDocView(props)); err != nil {
+			return nil, fmt.Errorf("%s > cannot export node '%s' to file '%s': %w", n.repo.instance.ID(), node.path, file, err)
+		}
+		paths = append(paths, node.path)
+	}
+	log.Infof("%s > exported node '%s' to dir '%s'", n.repo.instance.ID(), n.path, dir)
+	return paths, nil
+}
+
+func (n RepoNode) fileDir() string {
+	names := lo.Map(n.Breadcrumb(), func(node RepoNode, _ int) string { return EscapeNodeName(node.Name()) })
+	return filepath.Join(names...)
+}
+
+// ImportWithChanged saves nodes read from dir previously exported (only nodes at or below this node are imported)
+func (n RepoNode) ImportWithChanged(dir string) ([]string, error) {
+	log.Infof("%s > importing node '%s' from dir '%s'", n.repo.instance.ID(), n.path, dir)
+	files, err := docViewFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s > cannot import node '%s' from dir '%s': %w", n.repo.instance.ID(), n.path, dir, err)
+	}
+	var changedPaths []string
+	for _, file := range files {
+		node := n.repo.Node(docViewNodePath(dir, file))
+		if !node.IsSelfOrDescendantOf(n) {
+			continue
+		}
+		fileReader, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s > cannot open file '%s': %w", n.repo.instance.ID(), file, err)
+		}
+		props, err := UnmarshalDocView(fileReader)
+		fileReader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s > cannot import node '%s' from file '%s': %w", n.repo.instance.ID(), node.path, file, err)
+		}
+		changed, err := node.SaveWithChanged(props)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			changedPaths = append(changedPaths, node.path)
+		}
+	}
+	log.Infof("%s > imported node '%s' from dir '%s'", n.repo.instance.ID(), n.path, dir)
+	return changedPaths, nil
+}
+
+// docViewFiles finds '.content.xml' files ordered so that parent nodes are saved before their children
+func docViewFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && entry.Name() == DocViewFile {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return strings.Count(files[i], string(filepath.Separator)) < strings.Count(files[j], string(filepath.Separator))
+	})
+	return files, nil
+}
+
+func docViewNodePath(dir string, file string) string {
+	relativeDir, _ := filepath.Rel(dir, filepath.Dir(file))
+	if relativeDir == "." {
+		return "/"
+	}
+	names := lo.Map(strings.Split(filepath.ToSlash(relativeDir), "/"), func(name string, _ int) string { return UnescapeNodeName(name) })
+	return "/" + strings.Join(names, "/")
+}
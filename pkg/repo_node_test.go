@@ -0,0 +1,109 @@
+package pkg_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRepoNodeImportTwice(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var mutex sync.Mutex
+	nodes := map[string]map[string]any{}
+	writes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch r.Method {
+		case http.MethodHead, http.MethodGet:
+			props, ok := nodes[strings.TrimSuffix(r.URL.Path, ".json")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(props)
+		case http.MethodPost:
+			a.NoError(r.ParseForm())
+			props, ok := nodes[r.URL.Path]
+			if !ok {
+				props = map[string]any{"jcr:created": "Mon Jan 02 2023 10:00:00 GMT+0100"}
+				nodes[r.URL.Path] = props
+			}
+			for name, values := range r.PostForm {
+				if strings.HasPrefix(name, ":") || strings.Contains(name, "@") {
+					continue
+				}
+				props[name] = slingJSONValue(r.PostForm.Get(name+"@TypeHint"), values)
+			}
+			writes++
+			_, _ = w.Write([]byte(`{"status.code": 200}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	a.Nil(os.MkdirAll(filepath.Join(dir, "content/my-site/_jcr_content"), 0755))
+	a.Nil(os.WriteFile(filepath.Join(dir, "content/my-site/.content.xml"), []byte(pkg.MarshalDocView(map[string]any{
+		"jcr:primaryType": "cq:Page",
+	})), 0644))
+	a.Nil(os.WriteFile(filepath.Join(dir, "content/my-site/_jcr_content/.content.xml"), []byte(pkg.MarshalDocView(map[string]any{
+		"jcr:primaryType": "cq:PageContent",
+		"jcr:title":       "My Site",
+		"hidden":          false,
+		"order":           float64(3),
+		"ratio":           1.5,
+		"tags":            []any{"a", "b"},
+		"sizes":           []any{float64(1), float64(2)},
+		"cq:lastModified": "Tue Mar 14 2023 09:30:00 GMT+0100",
+	})), 0644))
+
+	node := pkg.DefaultAEM().InstanceManager().New("local_author", server.URL, "admin", "admin").Repo().Node("/content/my-site")
+
+	changed, err := node.ImportWithChanged(dir)
+	a.NoError(err)
+	a.Equal([]string{"/content/my-site", "/content/my-site/jcr:content"}, changed)
+	a.Equal(2, writes)
+
+	changed, err = node.ImportWithChanged(dir)
+	a.NoError(err)
+	a.Empty(changed)
+	a.Equal(2, writes)
+}
+
+// slingJSONValue mimics how Sling saves property using type hint and then renders it as JSON
+func slingJSONValue(typeHint string, values []string) any {
+	scalar := func(value string) any {
+		switch strings.TrimSuffix(typeHint, "[]") {
+		case "Boolean":
+			result, _ := strconv.ParseBool(value)
+			return result
+		case "Long", "Decimal":
+			result, _ := strconv.ParseFloat(value, 64)
+			return result
+		case "Date":
+			result, _ := time.Parse(pkg.DocViewDateLayout, value)
+			return result.Format(pkg.PropDateLayoutJSON)
+		default:
+			return value
+		}
+	}
+	if strings.HasSuffix(typeHint, "[]") {
+		result := make([]any, len(values))
+		for i, value := range values {
+			result[i] = scalar(value)
+		}
+		return result
+	}
+	return scalar(values[0])
+}
@@ -0,0 +1,40 @@
+package pkg_test
+
+import (
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRepoQueryRange(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != pkg.QuerySQL2Path {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// endpoint ignores paging params and always returns all results
+		_, _ = w.Write([]byte(`{"success": true, "total": 5, "results": [
+			{"path": "/content/n1"}, {"path": "/content/n2"}, {"path": "/content/n3"}, {"path": "/content/n4"}, {"path": "/content/n5"}
+		]}`))
+	}))
+	defer server.Close()
+
+	repo := pkg.DefaultAEM().InstanceManager().New("local_author", server.URL, "admin", "admin").Repo()
+	paths := func(offset int, limit int) []string {
+		nodes, err := repo.QueryRange("SELECT * FROM [nt:base]", offset, limit)
+		a.NoError(err)
+		return lo.Map(nodes, func(n pkg.RepoNode, _ int) string { return n.Path() })
+	}
+
+	a.Equal([]string{"/content/n1", "/content/n2", "/content/n3", "/content/n4", "/content/n5"}, paths(0, 0))
+	a.Equal([]string{"/content/n1", "/content/n2"}, paths(0, 2))
+	a.Equal([]string{"/content/n3", "/content/n4"}, paths(2, 2))
+	a.Equal([]string{"/content/n4", "/content/n5"}, paths(3, 0))
+	a.Equal([]string{}, paths(10, 2))
+}
@@ -0,0 +1,188 @@
+package pkg
+
+import (
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/wttech/aemc/pkg/common/fmtx"
+	"github.com/wttech/aemc/pkg/common/stringsx"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// RepoTraversal walks node tree in parallel; each request reads several levels at once using Sling depth selectors (e.g. '.2.json')
+type RepoTraversal struct {
+	Root        RepoNode
+	MaxDepth    int      // relative to root, negative means unlimited
+	Includes    []string // path globs of nodes to be returned (descendants of not included nodes are still visited)
+	Excludes    []string // path globs of nodes to be skipped together with descendants
+	NodeTypes   []string // primary types of nodes to be returned
+	Concurrency int
+	BatchDepth  int
+}
+
+// RepoTraversalResult is a node visited during traversal with properties already read
+type RepoTraversalResult struct {
+	Node  RepoNode
+	Depth int
+	Props map[string]any
+	Err   error
+}
+
+func (n RepoNode) Traversal() RepoTraversal {
+	return RepoTraversal{
+		Root:        n,
+		MaxDepth:    -1,
+		Concurrency: n.repo.TraversalConcurrency,
+		BatchDepth:  n.repo.TraversalBatchDepth,
+	}
+}
+
+// Walk streams visited nodes through channel which is closed when traversal ends; channel must be fully consumed
+func (t RepoTraversal) Walk() <-chan RepoTraversalResult {
+	results := make(chan RepoTraversalResult)
+	go func() {
+		defer close(results)
+		var wg sync.WaitGroup
+		workers := make(chan struct{}, lo.Max([]int{t.Concurrency, 1}))
+		var visit func(node RepoNode, depth int)
+		visit = func(node RepoNode, depth int) {
+			defer wg.Done()
+			workers <- struct{}{}
+			frontier, err := t.visitBatch(node, depth, results)
+			<-workers
+			if err != nil {
+				results <- RepoTraversalResult{Node: node, Depth: depth, Err: err}
+				return
+			}
+			for _, next := range frontier {
+				wg.Add(1)
+				go visit(next.Node, next.Depth)
+			}
+		}
+		if !t.Excluded(t.Root) {
+			wg.Add(1)
+			go visit(t.Root, 0)
+		}
+		wg.Wait()
+	}()
+	return results
+}
+
+// Collect walks through whole tree and returns matching nodes sorted by path
+func (t RepoTraversal) Collect() ([]RepoTraversalResult, error) {
+	var items []RepoTraversalResult
+	var errs []error
+	for result := range t.Walk() {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		} else {
+			items = append(items, result)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s > cannot traverse node '%s': %w", t.Root.repo.instance.ID(), t.Root.path, errs[0])
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Node.path < items[j].Node.path })
+	return items, nil
+}
+
+// Nodes is like Collect but returns nodes only
+func (t RepoTraversal) Nodes() ([]RepoNode, error) {
+	items, err := t.Collect()
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(items, func(r RepoTraversalResult, _ int) RepoNode { return r.Node }), nil
+}
+
+func (t RepoTraversal) Excluded(node RepoNode) bool {
+	return stringsx.MatchSome(node.path, t.Excludes)
+}
+
+func (t RepoTraversal) Matches(node RepoNode, props map[string]any) bool {
+	if len(t.Includes) > 0 && !stringsx.MatchSome(node.path, t.Includes) {
+		return false
+	}
+	if len(t.NodeTypes) > 0 && !lo.Contains(t.NodeTypes, fmt.Sprintf("%v", props["jcr:primaryType"])) {
+		return false
+	}
+	return true
+}
+
+// visitBatch reads node with descendants up to batch depth, emits them and returns nodes whose children are not yet read
+func (t RepoTraversal) visitBatch(node RepoNode, depth int, results chan<- RepoTraversalResult) ([]RepoTraversalResult, error) {
+	fetchDepth := lo.Max([]int{t.BatchDepth, 1})
+	terminal := false
+	if t.MaxDepth >= 0 && t.MaxDepth-depth <= fetchDepth {
+		fetchDepth = t.MaxDepth - depth
+		terminal = true
+	}
+	tree, fetchDepth, err := t.read(node, fetchDepth, terminal)
+	if err != nil {
+		return nil, err
+	}
+	if fetchDepth < t.MaxDepth-depth {
+		terminal = false
+	}
+	var frontier []RepoTraversalResult
+	var emit func(current RepoNode, data map[string]any, level int)
+	emit = func(current RepoNode, data map[string]any, level int) {
+		if level == fetchDepth && !terminal {
+			frontier = append(frontier, RepoTraversalResult{Node: current, Depth: depth + level})
+			return
+		}
+		props := map[string]any{}
+		var children []string
+		for name, value := range data {
+			if _, ok := value.(map[string]any); ok {
+				children = append(children, name)
+			} else {
+				props[name] = value
+			}
+		}
+		if t.Matches(current, props) {
+			results <- RepoTraversalResult{Node: current, Depth: depth + level, Props: props}
+		}
+		if level == fetchDepth {
+			return
+		}
+		sort.Strings(children)
+		for _, name := range children {
+			child := current.Child(name)
+			if !t.Excluded(child) {
+				emit(child, data[name].(map[string]any), level+1)
+			}
+		}
+	}
+	emit(node, tree, 0)
+	return frontier, nil
+}
+
+// read requests node tree; when Sling refuses to render too many nodes at once, lower depth is used
+func (t RepoTraversal) read(node RepoNode, depth int, terminal bool) (map[string]any, int, error) {
+	minDepth := 1
+	if terminal {
+		minDepth = 0
+	}
+	for {
+		response, err := node.repo.instance.http.Request().Get(fmt.Sprintf("%s.%d.json", node.path, depth))
+		if err != nil {
+			return nil, depth, fmt.Errorf("cannot read node tree '%s': %w", node.path, err)
+		}
+		if response.StatusCode() == http.StatusMultipleChoices && depth > minDepth {
+			_ = response.RawBody().Close()
+			depth--
+			continue
+		}
+		if response.IsError() || response.StatusCode() != http.StatusOK {
+			_ = response.RawBody().Close()
+			return nil, depth, fmt.Errorf("cannot read node tree '%s': %s", node.path, response.Status())
+		}
+		var tree map[string]any
+		if err = fmtx.UnmarshalJSON(response.RawBody(), &tree); err != nil {
+			return nil, depth, fmt.Errorf("cannot parse node tree '%s': %w", node.path, err)
+		}
+		return tree, depth, nil
+	}
+}
@@ -0,0 +1,150 @@
+package pkg_test
+
+import (
+	"encoding/json"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/wttech/aemc/pkg"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRepoTraversalCollect(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	server, requests := newSlingTreeServer(map[string]bool{})
+	defer server.Close()
+
+	traversal := newRepoTraversal(server)
+	traversal.Excludes = []string{"/content/excluded"}
+	items, err := traversal.Collect()
+	a.NoError(err)
+	a.Equal([]string{
+		"/content",
+		"/content/a",
+		"/content/a/a1",
+		"/content/a/a1/a11",
+		"/content/a/a1/a11/a111",
+		"/content/b",
+		"/content/b/b1",
+	}, lo.Map(items, func(item pkg.RepoTraversalResult, _ int) string { return item.Node.Path() }))
+	a.Equal("cq:Page", items[1].Props["jcr:primaryType"])
+	a.Equal(4, items[4].Depth)
+
+	// batch too deep is refused by Sling so lower depth is used, next batches start from not yet read nodes
+	a.ElementsMatch([]string{
+		"/content.3.json", "/content.2.json",
+		"/content/a/a1.3.json", "/content/a/a1.2.json",
+		"/content/b/b1.3.json", "/content/b/b1.2.json",
+		"/content/a/a1/a11/a111.3.json", "/content/a/a1/a11/a111.2.json",
+	}, requests())
+}
+
+func TestRepoTraversalMaxDepth(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	server, requests := newSlingTreeServer(map[string]bool{})
+	defer server.Close()
+
+	traversal := newRepoTraversal(server)
+	traversal.MaxDepth = 2
+	traversal.Excludes = []string{"/content/excluded"}
+	nodes, err := traversal.Nodes()
+	a.NoError(err)
+	a.Equal([]string{"/content", "/content/a", "/content/a/a1", "/content/b", "/content/b/b1"}, lo.Map(nodes, func(n pkg.RepoNode, _ int) string { return n.Path() }))
+	a.Equal([]string{"/content.2.json"}, requests())
+}
+
+func TestRepoTraversalError(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	server, _ := newSlingTreeServer(map[string]bool{"/content/b": true})
+	defer server.Close()
+
+	traversal := newRepoTraversal(server)
+	traversal.BatchDepth = 1
+	_, err := traversal.Collect()
+	a.ErrorContains(err, "/content/b")
+}
+
+func newRepoTraversal(server *httptest.Server) pkg.RepoTraversal {
+	traversal := pkg.DefaultAEM().InstanceManager().New("local_author", server.URL, "admin", "admin").Repo().Node("/content").Traversal()
+	traversal.Concurrency = 2
+	traversal.BatchDepth = 3
+	return traversal
+}
+
+var slingTreeRequest = regexp.MustCompile(`^(.+)\.(\d+)\.json$`)
+
+// newSlingTreeServer mimics Sling default GET servlet rendering node trees but refusing to render more than 2 levels at once
+func newSlingTreeServer(failing map[string]bool) (*httptest.Server, func() []string) {
+	tree := map[string]any{"jcr:primaryType": "sling:Folder",
+		"a": map[string]any{"jcr:primaryType": "cq:Page",
+			"a1": map[string]any{"jcr:primaryType": "cq:Page",
+				"a11": map[string]any{"jcr:primaryType": "cq:Page",
+					"a111": map[string]any{"jcr:primaryType": "cq:Page"},
+				},
+			},
+		},
+		"b": map[string]any{"jcr:primaryType": "cq:Page",
+			"b1": map[string]any{"jcr:primaryType": "cq:Page"},
+		},
+		"excluded": map[string]any{"jcr:primaryType": "sling:Folder",
+			"e1": map[string]any{"jcr:primaryType": "nt:unstructured"},
+		},
+	}
+	var mutex sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, r.URL.Path)
+		mutex.Unlock()
+
+		matches := slingTreeRequest.FindStringSubmatch(r.URL.Path)
+		if matches == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		path := matches[1]
+		depth, _ := strconv.Atoi(matches[2])
+		if failing[path] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		node := tree
+		for _, name := range strings.Split(strings.TrimPrefix(path, "/content"), "/")[1:] {
+			node = node[name].(map[string]any)
+		}
+		if depth > 2 {
+			w.WriteHeader(http.StatusMultipleChoices)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(slingTreeLevels(node, depth))
+	}))
+	return server, func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string{}, requests...)
+	}
+}
+
+func slingTreeLevels(node map[string]any, depth int) map[string]any {
+	result := map[string]any{}
+	for name, value := range node {
+		child, ok := value.(map[string]any)
+		if !ok {
+			result[name] = value
+		} else if depth > 0 {
+			result[name] = slingTreeLevels(child, depth-1)
+		}
+	}
+	return result
+}